@@ -9,6 +9,7 @@ import (
 	"github.com/stjudewashere/seonaut/internal/config"
 	"github.com/stjudewashere/seonaut/internal/crawler"
 	"github.com/stjudewashere/seonaut/internal/datastore"
+	"github.com/stjudewashere/seonaut/internal/diff"
 	"github.com/stjudewashere/seonaut/internal/export"
 	"github.com/stjudewashere/seonaut/internal/http"
 	"github.com/stjudewashere/seonaut/internal/issue"
@@ -19,6 +20,7 @@ import (
 	"github.com/stjudewashere/seonaut/internal/report_manager"
 	"github.com/stjudewashere/seonaut/internal/report_manager/reporters"
 	"github.com/stjudewashere/seonaut/internal/report_manager/sql_reporters"
+	"github.com/stjudewashere/seonaut/internal/scheduler"
 	"github.com/stjudewashere/seonaut/internal/user"
 )
 
@@ -51,7 +53,7 @@ func main() {
 		log.Fatalf("Error running migrations: %v\n", err)
 	}
 
-	// Delete any unfinished crawls.
+	// Delete any unfinished crawls that can't be resumed.
 	unfinishedCrawls := ds.DeleteUnfinishedCrawls()
 	log.Printf("Deleted %d unfinished crawls.", unfinishedCrawls)
 
@@ -67,7 +69,7 @@ func main() {
 	cacheManager.AddCrawlCacheHandler(reportService)
 
 	reportManager := report_manager.NewReportManager(ds)
-	for _, r := range reporters.GetAllReporters() {
+	for _, r := range reporters.GetAllReporters(config.Reporters) {
 		reportManager.AddPageReporter(r)
 	}
 
@@ -77,17 +79,27 @@ func main() {
 		reportManager.AddMultipageReporter(r)
 	}
 
+	crawlerService := crawler.NewService(ds, broker, config.Crawler, cacheManager, reportManager, issueService)
+
+	// Resume the crawls that were interrupted.
+	resumedCrawls := crawlerService.ResumeUnfinishedCrawls()
+	log.Printf("Resuming %d unfinished crawls.", resumedCrawls)
+
+	// Start the scheduler of the projects' recurring crawls.
+	scheduler.New(ds, crawlerService).Start()
+
 	// Start HTTP server.
 	services := &http.Services{
 		UserService:        user.NewService(ds),
 		ProjectService:     project.NewService(ds, cacheManager),
-		CrawlerService:     crawler.NewService(ds, broker, config.Crawler, cacheManager, reportManager),
+		CrawlerService:     crawlerService,
 		IssueService:       issueService,
 		ReportService:      reportService,
 		ReportManager:      reportManager,
 		ProjectViewService: projectview.NewService(ds),
 		PubSubBroker:       broker,
 		ExportService:      export.NewExporter(ds),
+		DiffService:        diff.NewService(ds),
 	}
 
 	server := http.NewApp(
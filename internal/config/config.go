@@ -1,10 +1,14 @@
 package config
 
 import (
+	"fmt"
+
 	"github.com/stjudewashere/seonaut/internal/cache"
 	"github.com/stjudewashere/seonaut/internal/crawler"
 	"github.com/stjudewashere/seonaut/internal/datastore"
 	"github.com/stjudewashere/seonaut/internal/http"
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+	"github.com/stjudewashere/seonaut/internal/report_manager/reporters"
 
 	"github.com/spf13/viper"
 )
@@ -15,6 +19,7 @@ type Config struct {
 	HTTPServer *http.HTTPServerConfig `mapstructure:"server"`
 	DB         *datastore.DBConfig    `mapstructure:"database"`
 	Cache      *cache.Config          `mapstructure:"redis"`
+	Reporters  *reporters.Config      `mapstructure:"reporters"`
 }
 
 // NewConfig loads the configuration from the specified file and path.
@@ -23,6 +28,10 @@ func NewConfig(path, filename string) (*Config, error) {
 	viper.SetConfigName(filename)
 	viper.SetConfigType("toml")
 
+	viper.SetDefault("crawler.retries", 2)
+	viper.SetDefault("crawler.retry_delay", 1000)
+	viper.SetDefault("crawler.mobile_agent", "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 (compatible; SEOnautBot/1.0; +https://seonaut.org/bot)")
+
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
 	}
@@ -34,5 +43,12 @@ func NewConfig(path, filename string) (*Config, error) {
 		return nil, err
 	}
 
+	// The crawls would be sent without the proxy if it was not valid.
+	if config.Crawler != nil {
+		if _, err := httpcrawler.ParseProxy(config.Crawler.Proxy); err != nil {
+			return nil, fmt.Errorf("crawler proxy: %w", err)
+		}
+	}
+
 	return &config, nil
 }
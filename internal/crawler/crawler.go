@@ -3,18 +3,40 @@ package crawler
 import (
 	"context"
 	"log"
+	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/html_parser"
 	"github.com/stjudewashere/seonaut/internal/httpcrawler"
 	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/queue"
+	"github.com/stjudewashere/seonaut/internal/urlfilter"
+	"github.com/stjudewashere/seonaut/internal/urlnormalizer"
 	"github.com/stjudewashere/seonaut/internal/urlstorage"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// Interval between the crawler's frontier checkpoints.
+	checkpointInterval = 30 * time.Second
+
+	// Max robots.txt Crawl-delay honored by the crawler.
+	maxCrawlDelay = 30 * time.Second
 )
 
 type Options struct {
 	MaxPageReports  int
+	MaxDepth        int           // Max depth of the queued URLs, 0 means no limit
+	Threads         int           // Number of concurrent requests
+	Delay           time.Duration // Max random delay before each request
+	Timeout         time.Duration // HTTP requests timeout
+	Retries         int           // Retries of failed requests
+	RetryDelay      time.Duration // Delay before the first retry
 	IgnoreRobotsTxt bool
 	FollowNofollow  bool
 	IncludeNoindex  bool
@@ -24,6 +46,19 @@ type Options struct {
 	BasicAuth       bool
 	AuthUser        string
 	AuthPass        string
+	Headers         http.Header // Custom headers sent to the crawled domain
+	UseCookies      bool        // Store the cookies set by the crawled domain and send them back
+	LoginURL        string      // If set, LoginData is posted to this URL before crawling
+	LoginData       url.Values
+	Proxy           *url.URL                  // If set, all the requests are made through this proxy
+	Frontier        *models.Frontier          // If set, the crawl is resumed from this checkpoint
+	ListMode        bool                      // Only the queued URLs are crawled, links are not followed
+	URLs            []string                  // URLs crawled in list mode instead of the crawler's URL
+	Renderer        httpcrawler.Renderer      // If set, HTML pages are rendered executing their JavaScript
+	Filter          *urlfilter.Filter         // If set, only the URLs allowed by the filter are queued
+	Normalizer      *urlnormalizer.Normalizer // If set, URLs are normalized before they are queued
+	RobotsDraft     string                    // If set, it is used instead of the live robots.txt of the crawled host
+	RobotsToken     string                    // If set, robots directives are evaluated for this bot token
 }
 
 type Crawler struct {
@@ -34,6 +69,7 @@ type Crawler struct {
 	sitemapStorage  *urlstorage.URLStorage
 	sitemapChecker  *httpcrawler.SitemapChecker
 	sitemapExists   bool
+	blockedURLs     []*url.URL
 	sitemaps        []string
 	robotstxtExists bool
 	responseCounter int
@@ -41,11 +77,20 @@ type Crawler struct {
 	prStream        chan *models.PageReportMessage
 	allowedDomains  map[string]bool
 	mainDomain      string
+	client          *httpcrawler.BasicAuthClient
 	httpCrawler     *httpcrawler.HttpCrawler
 	qStream         chan *httpcrawler.RequestMessage
+	stop            chan struct{}
+	stopOnce        *sync.Once
+	resume          chan struct{}
+	pauseLock       *sync.Mutex
+	certificates    *httpcrawler.CertificateStore
+	sentHosts       map[string]bool // Hosts whose certificate has been sent to be stored
+	sentRobots      map[string]bool // Hosts whose robots.txt file has been sent to be stored
 }
 
 func NewCrawler(url *url.URL, options *Options) *Crawler {
+	url = options.Normalizer.Normalize(url)
 	mainDomain := strings.TrimPrefix(url.Host, "www.")
 
 	if url.Path == "" {
@@ -53,26 +98,59 @@ func NewCrawler(url *url.URL, options *Options) *Crawler {
 	}
 
 	storage := urlstorage.New()
-	storage.Add(url.String())
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	q := queue.New(ctx)
-	q.Push(&httpcrawler.RequestMessage{URL: url.String()})
-
-	httpClient := httpcrawler.NewClient(&httpcrawler.ClientOptions{
-		UserAgent:        options.UserAgent,
-		BasicAuth:        options.BasicAuth,
-		BasicAuthDomains: []string{mainDomain, "www." + mainDomain},
-		AuthUser:         options.AuthUser,
-		AuthPass:         options.AuthPass,
-	})
+
+	responseCounter := 0
+	if options.Frontier != nil {
+		for _, u := range options.Frontier.Seen {
+			storage.Add(u)
+		}
+
+		responseCounter = options.Frontier.Crawled
+	}
+
+	// The frontier checkpoints only include the URLs seen since the previous checkpoint.
+	// The queued URLs of a resumed crawl are tracked again so they are kept as seen URLs.
+	storage.Track()
+
+	if options.Frontier != nil {
+		for _, u := range options.Frontier.Queued {
+			storage.Add(u.URL)
+			q.Push(&httpcrawler.RequestMessage{URL: u.URL, Depth: u.Depth})
+		}
+	} else if !options.ListMode {
+		storage.Add(url.String())
+		q.Push(&httpcrawler.RequestMessage{URL: url.String()})
+	}
+
+	clientOptions := &httpcrawler.ClientOptions{
+		UserAgent:    options.UserAgent,
+		BasicAuth:    options.BasicAuth,
+		AuthDomains:  []string{mainDomain, "www." + mainDomain},
+		AuthUser:     options.AuthUser,
+		AuthPass:     options.AuthPass,
+		Timeout:      options.Timeout,
+		Headers:      options.Headers,
+		Proxy:        options.Proxy,
+		Certificates: httpcrawler.NewCertificateStore(),
+	}
+
+	if options.UseCookies || options.LoginURL != "" {
+		clientOptions.CookieJar, _ = cookiejar.New(nil)
+	}
+
+	httpClient := httpcrawler.NewClient(clientOptions)
 
 	robotsChecker := httpcrawler.NewRobotsChecker(httpClient, options.UserAgent)
+	if options.RobotsDraft != "" {
+		robotsChecker.SetDraft(url.Host, options.RobotsDraft)
+	}
 
-	sitemaps := robotsChecker.GetSitemaps(url)
-	if len(sitemaps) == 0 {
-		sitemaps = []string{url.Scheme + "://" + url.Host + "/sitemap.xml"}
+	if options.RobotsToken != "" {
+		robotsChecker.SetToken(options.RobotsToken)
 	}
 
 	sitemapChecker := httpcrawler.NewSitemapChecker(httpClient, options.MaxPageReports)
@@ -85,17 +163,42 @@ func NewCrawler(url *url.URL, options *Options) *Crawler {
 		storage:         storage,
 		sitemapStorage:  urlstorage.New(),
 		sitemapChecker:  sitemapChecker,
-		sitemapExists:   sitemapChecker.SitemapExists(sitemaps),
-		sitemaps:        sitemaps,
 		robotsChecker:   robotsChecker,
-		robotstxtExists: robotsChecker.Exists(url),
+		responseCounter: responseCounter,
 		allowedDomains:  map[string]bool{mainDomain: true, "www." + mainDomain: true},
 		mainDomain:      mainDomain,
 		prStream:        make(chan *models.PageReportMessage),
+		client:          httpClient,
 		qStream:         qStream,
-		httpCrawler:     httpcrawler.New(httpClient, qStream),
+		stop:            make(chan struct{}),
+		stopOnce:        &sync.Once{},
+		pauseLock:       &sync.Mutex{},
+		certificates:    clientOptions.Certificates,
+		sentHosts:       make(map[string]bool),
+		sentRobots:      make(map[string]bool),
 	}
 
+	c.httpCrawler = httpcrawler.New(httpClient, qStream, &httpcrawler.Options{
+		Threads:        options.Threads,
+		RandomDelay:    options.Delay,
+		RateLimiter:    httpcrawler.NewRateLimiter(crawlDelay(robotsChecker, options)),
+		Retries:        options.Retries,
+		RetryDelay:     options.RetryDelay,
+		Renderer:       options.Renderer,
+		Session:        clientOptions,
+		FollowRedirect: c.followRedirect,
+	})
+
+	// The context is cancelled as soon as the crawler is stopped,
+	// so the crawl's setup and the parsing of the sitemaps don't block it.
+	go func() {
+		select {
+		case <-c.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	go func() {
 		defer close(c.qStream)
 		c.queueStreamer(ctx)
@@ -103,13 +206,92 @@ func NewCrawler(url *url.URL, options *Options) *Crawler {
 
 	go func() {
 		defer close(c.prStream)
-		c.crawl(ctx)
+		if c.setup(ctx) {
+			c.crawl(ctx)
+		}
 		cancel()
 	}()
 
 	return c
 }
 
+// Prepares the crawl before any URL is requested. It logs in, checks the robots.txt
+// and sitemap files and queues the list mode URLs. The setup is done once the crawler
+// has been created, so it can be stopped in the meantime. It returns false if the
+// context is done before the setup is completed.
+func (c *Crawler) setup(ctx context.Context) bool {
+	if c.options.LoginURL != "" {
+		if err := c.client.Login(c.options.LoginURL, c.options.LoginData); err != nil {
+			log.Printf("Crawler login %s: %v\n", c.url, err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return false
+	}
+
+	c.sitemaps = c.robotsChecker.GetSitemaps(c.url)
+	if len(c.sitemaps) == 0 {
+		c.sitemaps = []string{c.url.Scheme + "://" + c.url.Host + "/sitemap.xml"}
+	}
+
+	c.robotstxtExists = c.robotsChecker.Exists(c.url)
+	c.blockedURLs = queueList(c.queue, c.storage, c.robotsChecker, c.options)
+
+	if ctx.Err() != nil {
+		return false
+	}
+
+	c.sitemapExists = c.sitemapChecker.SitemapExists(c.sitemaps)
+
+	return ctx.Err() == nil
+}
+
+// Queues the list mode URLs unless the crawl is resumed from a frontier checkpoint.
+// The URLs blocked by the robots.txt are not queued, they are returned so they can
+// be reported as blocked once the crawl starts.
+func queueList(q *queue.Queue, storage *urlstorage.URLStorage, r *httpcrawler.RobotsChecker, options *Options) []*url.URL {
+	if !options.ListMode || options.Frontier != nil {
+		return nil
+	}
+
+	var blocked []*url.URL
+	for _, s := range options.URLs {
+		u, err := url.Parse(s)
+		if err != nil {
+			continue
+		}
+
+		u = options.Normalizer.Normalize(u)
+		if storage.Seen(u.String()) {
+			continue
+		}
+
+		storage.Add(u.String())
+
+		if !options.IgnoreRobotsTxt && r.IsBlocked(u) {
+			blocked = append(blocked, u)
+			continue
+		}
+
+		q.Push(&httpcrawler.RequestMessage{URL: u.String()})
+	}
+
+	return blocked
+}
+
+// Returns the function used by the rate limiter to get the delay between requests to a host.
+// The robots.txt Crawl-delay is honored unless the robots.txt file is ignored.
+func crawlDelay(r *httpcrawler.RobotsChecker, options *Options) func(*url.URL) time.Duration {
+	if options.IgnoreRobotsTxt {
+		return nil
+	}
+
+	return func(u *url.URL) time.Duration {
+		return min(r.CrawlDelay(u), maxCrawlDelay)
+	}
+}
+
 // Returns the PageReportMessage channel that streams all generated PageReports
 // into a PageReportMessage struct.
 func (c *Crawler) Stream() <-chan *models.PageReportMessage {
@@ -117,9 +299,18 @@ func (c *Crawler) Stream() <-chan *models.PageReportMessage {
 }
 
 // Polls URLs from the queue and sends them into the qStream channel.
+// While the crawler is paused no URLs are sent.
 // queueStreamer shuts down when the ctx context is done.
 func (c *Crawler) queueStreamer(ctx context.Context) {
 	for {
+		if resume := c.resumeChan(); resume != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-resume:
+			}
+		}
+
 		select {
 		case <-ctx.Done():
 			return
@@ -129,53 +320,197 @@ func (c *Crawler) queueStreamer(ctx context.Context) {
 }
 
 // Crawl starts crawling an URL and sends pagereports of the crawled URLs
-// through the pr channel. It will end when there are no more URLs to crawl
-// or the MaxPageReports limit is hit.
+// through the pr channel. It will end when there are no more URLs to crawl,
+// the MaxPageReports limit is hit or the crawler is stopped.
 func (c *Crawler) crawl(ctx context.Context) {
+	c.robotstxt(c.url)
+
 	if c.sitemapExists && c.options.CrawlSitemap {
-		c.sitemapChecker.ParseSitemaps(c.sitemaps, c.loadSitemapURLs)
+		files := c.sitemapChecker.ParseSitemaps(ctx, c.sitemaps, c.loadSitemapURLs)
+		if ctx.Err() != nil {
+			return
+		}
+
+		for _, f := range files {
+			c.prStream <- &models.PageReportMessage{
+				Sitemap:    f,
+				Crawled:    c.responseCounter,
+				Discovered: c.queue.Count(),
+			}
+		}
 	}
 
 	sitemapLoaded := false
+	lastCheckpoint := time.Now()
+	responses := c.httpCrawler.Crawl(ctx)
 
-	for rm := range c.httpCrawler.Crawl(ctx) {
-		err := c.handleResponse(rm)
-		if err != nil {
-			log.Printf("handleResponse %s: Error %v", rm.URL, err)
+	for _, u := range c.blockedURLs {
+		c.prStream <- &models.PageReportMessage{
+			PageReport: &models.PageReport{
+				URL:                u.String(),
+				ParsedURL:          u,
+				BlockedByRobotstxt: true,
+			},
+			HtmlNode:   &html.Node{},
+			Header:     &http.Header{},
+			Crawled:    c.responseCounter,
+			Discovered: c.queue.Count(),
 		}
+	}
 
-		if !c.queue.Active() && c.options.CrawlSitemap && !sitemapLoaded {
-			c.queueSitemapURLs()
-			sitemapLoaded = true
-		}
+	// A resumed crawl may not have any queued URLs left.
+	if !c.queue.Active() && c.options.CrawlSitemap {
+		c.queueSitemapURLs()
+		sitemapLoaded = true
+	}
 
-		if !c.queue.Active() || c.responseCounter >= c.options.MaxPageReports {
-			break
+	if !c.queue.Active() {
+		return
+	}
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case rm, ok := <-responses:
+			if !ok {
+				return
+			}
+
+			err := c.handleResponse(rm)
+			if err != nil {
+				log.Printf("handleResponse %s: Error %v", rm.URL, err)
+			}
+
+			if !c.queue.Active() && c.options.CrawlSitemap && !sitemapLoaded {
+				c.queueSitemapURLs()
+				sitemapLoaded = true
+			}
+
+			if !c.queue.Active() || c.responseCounter >= c.options.MaxPageReports {
+				return
+			}
+
+			if time.Since(lastCheckpoint) >= checkpointInterval {
+				c.prStream <- &models.PageReportMessage{Frontier: c.frontier()}
+				lastCheckpoint = time.Now()
+			}
 		}
 	}
 }
 
+// Returns a checkpoint of the crawler's state with the URLs seen since the previous
+// checkpoint. It must be called from the crawl goroutine so the state is not modified
+// while the checkpoint is created.
+func (c *Crawler) frontier() *models.Frontier {
+	f := &models.Frontier{
+		Seen:    c.storage.Added(),
+		Crawled: c.responseCounter,
+	}
+
+	for _, rm := range c.queue.Snapshot() {
+		f.Queued = append(f.Queued, models.FrontierURL{URL: rm.URL, Depth: rm.Depth})
+	}
+
+	return f
+}
+
+// Stop stops the crawler. The page reports of the URLs crawled so far are
+// still streamed and the Stream channel is closed once the crawler has stopped.
+func (c *Crawler) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// Stopped returns true if the crawler has been stopped.
+func (c *Crawler) Stopped() bool {
+	select {
+	case <-c.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Pause stops sending new URLs to the http crawler until Resume is called.
+// The requests that are already in progress are completed.
+func (c *Crawler) Pause() {
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+
+	if c.resume == nil {
+		c.resume = make(chan struct{})
+	}
+}
+
+// Resume resumes a paused crawler.
+func (c *Crawler) Resume() {
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+
+	if c.resume != nil {
+		close(c.resume)
+		c.resume = nil
+	}
+}
+
+// Paused returns true if the crawler is paused.
+func (c *Crawler) Paused() bool {
+	return c.resumeChan() != nil
+}
+
+// Returns the channel that is closed when the crawler is resumed.
+// It returns nil if the crawler is not paused.
+func (c *Crawler) resumeChan() chan struct{} {
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+
+	return c.resume
+}
+
 // handleResponse handles the crawler response messages.
 // It creates a new PageReport and adds the new URLs to the crawler queue.
+// The responses of the redirect hops are handled next, instead of queueing their URLs.
 func (c *Crawler) handleResponse(r *httpcrawler.ResponseMessage) error {
 	c.queue.Ack(r.URL)
+	defer r.CloseRedirectResponses()
+
 	if r.Error != nil {
+		c.handleFetchError(r)
 		return r.Error
 	}
 
 	defer r.Response.Body.Close()
 
-	pageReport, htmlNode, err := html_parser.NewFromHTTPResponse(r.Response)
+	pageReport, htmlNode, err := html_parser.NewFromHTTPResponse(r.Response, c.options.RobotsToken)
 	if err != nil {
 		return err
 	}
 
+	if r.RenderError != nil {
+		log.Printf("Render %s: %v\n", r.URL, r.RenderError)
+	} else if r.Rendered != nil {
+		pageReport, htmlNode = c.rendered(r, pageReport, htmlNode)
+	}
+
 	parsedURL, err := url.Parse(r.URL)
 	if err != nil {
 		return err
 	}
 
 	pageReport.Depth = r.Depth
+	pageReport.RedirectHops = r.Redirects
+	if r.Timing != nil {
+		pageReport.Timing = *r.Timing
+	}
+
+	if r.Transfer != nil {
+		pageReport.Transfer = *r.Transfer
+	}
+
+	pageReport.TLS = c.certificate(parsedURL)
+	c.robotstxt(parsedURL)
 	pageReport.BlockedByRobotstxt = c.robotsChecker.IsBlocked(parsedURL)
 	pageReport.InSitemap = c.sitemapStorage.Seen(r.URL)
 
@@ -186,6 +521,32 @@ func (c *Crawler) handleResponse(r *httpcrawler.ResponseMessage) error {
 	pageReport.Crawled = true
 	c.responseCounter++
 
+	// Internal links and resources point to the normalized URLs so they match the crawled pages.
+	if c.options.Normalizer != nil {
+		for i, l := range pageReport.Links {
+			pageReport.Links[i].ParsedURL = c.normalize(l.ParsedURL)
+			pageReport.Links[i].URL = pageReport.Links[i].ParsedURL.String()
+		}
+
+		for i, image := range pageReport.Images {
+			pageReport.Images[i].URL = c.normalizeString(image.URL)
+		}
+
+		for _, resources := range [][]string{pageReport.Scripts, pageReport.Styles, pageReport.Audios, pageReport.Videos} {
+			for i, r := range resources {
+				resources[i] = c.normalizeString(r)
+			}
+		}
+	}
+
+	if c.options.ListMode {
+		c.sendPageReport(pageReport, htmlNode, &r.Response.Header)
+		return nil
+	}
+
+	// The redirect hops are marked as seen before the links are queued so they are not requested again.
+	redirects := c.redirectResponses(r)
+
 	crawlable := [][]*url.URL{
 		c.getCrawlableLinks(pageReport),
 		c.getResourceURLs(pageReport),
@@ -198,12 +559,21 @@ func (c *Crawler) handleResponse(r *httpcrawler.ResponseMessage) error {
 	}
 
 	for _, t := range urls {
+		t = c.normalize(t)
 		if c.storage.Seen(t.String()) {
 			continue
 		}
 
+		if c.options.MaxDepth > 0 && pageReport.Depth+1 > c.options.MaxDepth {
+			continue
+		}
+
 		c.storage.Add(t.String())
 
+		if c.excluded(t) {
+			continue
+		}
+
 		if !c.options.IgnoreRobotsTxt && c.robotsChecker.IsBlocked(t) {
 			c.prStream <- &models.PageReportMessage{
 				Crawled:    c.responseCounter,
@@ -224,17 +594,180 @@ func (c *Crawler) handleResponse(r *httpcrawler.ResponseMessage) error {
 		c.queue.Push(&httpcrawler.RequestMessage{URL: t.String(), Depth: pageReport.Depth + 1})
 	}
 
+	c.sendPageReport(pageReport, htmlNode, &r.Response.Header)
+
+	for i, rm := range redirects {
+		if c.responseCounter >= c.options.MaxPageReports {
+			r.RedirectResponses = append(r.RedirectResponses, redirects[i:]...)
+			break
+		}
+
+		if err := c.handleResponse(rm); err != nil {
+			log.Printf("handleResponse %s: Error %v", rm.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// Returns the responses of the redirect hops that are crawled as if their URLs had been
+// queued, marking the URLs as seen. Hops that have already been seen, are not normalized
+// or exceed the max depth are not crawled, they are left in the message to be closed.
+func (c *Crawler) redirectResponses(r *httpcrawler.ResponseMessage) []*httpcrawler.ResponseMessage {
+	crawled := []*httpcrawler.ResponseMessage{}
+	unused := []*httpcrawler.ResponseMessage{}
+
+	for _, rm := range r.RedirectResponses {
+		parsed, err := url.Parse(rm.URL)
+		if err != nil || c.normalize(parsed).String() != rm.URL || c.storage.Seen(rm.URL) ||
+			(c.options.MaxDepth > 0 && rm.Depth > c.options.MaxDepth) {
+			unused = append(unused, rm)
+			continue
+		}
+
+		c.storage.Add(rm.URL)
+		crawled = append(crawled, rm)
+	}
+
+	r.RedirectResponses = unused
+
+	return crawled
+}
+
+// Sends the PageReport through the prStream channel
+// unless it is noindex and noindex pages are not included.
+func (c *Crawler) sendPageReport(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) {
 	if !pageReport.Noindex || c.options.IncludeNoindex {
 		c.prStream <- &models.PageReportMessage{
 			PageReport: pageReport,
 			HtmlNode:   htmlNode,
-			Header:     &r.Response.Header,
+			Header:     header,
 			Crawled:    c.responseCounter,
 			Discovered: c.queue.Count(),
 		}
 	}
+}
 
-	return nil
+// rendered returns the PageReport and html node of the rendered DOM, parsed the same way
+// as the raw HTML, with a RenderDiff comparing it with the raw PageReport.
+// If the rendered DOM can't be parsed the raw PageReport and html node are returned.
+func (c *Crawler) rendered(r *httpcrawler.ResponseMessage, raw *models.PageReport, rawNode *html.Node) (*models.PageReport, *html.Node) {
+	pageReport, htmlNode, err := html_parser.New(raw.ParsedURL, r.Response.StatusCode, &r.Response.Header, r.Rendered, c.options.RobotsToken)
+	if err != nil {
+		log.Printf("Render %s: %v\n", r.URL, err)
+		return raw, rawNode
+	}
+
+	pageReport.Size = raw.Size
+	pageReport.RenderDiff = renderDiff(raw, pageReport)
+
+	return pageReport, htmlNode
+}
+
+// Returns a RenderDiff comparing the title, links and word count of the raw and rendered page reports.
+func renderDiff(raw, rendered *models.PageReport) *models.RenderDiff {
+	rawLinks := map[string]bool{}
+	for _, links := range [][]models.Link{raw.Links, raw.ExternalLinks} {
+		for _, l := range links {
+			rawLinks[l.URL] = true
+		}
+	}
+
+	added := 0
+	for _, links := range [][]models.Link{rendered.Links, rendered.ExternalLinks} {
+		for _, l := range links {
+			if !rawLinks[l.URL] {
+				added++
+			}
+		}
+	}
+
+	return &models.RenderDiff{
+		RawTitle:      raw.Title,
+		RenderedTitle: rendered.Title,
+		RawLinks:      len(raw.Links) + len(raw.ExternalLinks),
+		RenderedLinks: len(rendered.Links) + len(rendered.ExternalLinks),
+		AddedLinks:    added,
+		RawWords:      raw.Words,
+		RenderedWords: rendered.Words,
+	}
+}
+
+// handleFetchError sends a PageReport for a URL that could not be fetched or decoded,
+// storing the type of error so it is reported as an issue.
+func (c *Crawler) handleFetchError(r *httpcrawler.ResponseMessage) {
+	parsedURL, err := url.Parse(r.URL)
+	if err != nil {
+		return
+	}
+
+	pageReport := &models.PageReport{
+		URL:        r.URL,
+		ParsedURL:  parsedURL,
+		Crawled:    true,
+		Depth:      r.Depth,
+		InSitemap:  c.sitemapStorage.Seen(r.URL),
+		FetchError: httpcrawler.FetchError(r.Error),
+		TLS:        c.certificate(parsedURL),
+	}
+
+	// The transfer is kept if there was a response, for instance to record
+	// the content encoding of a response that could not be decoded.
+	if r.Transfer != nil {
+		pageReport.Transfer = *r.Transfer
+	}
+
+	c.responseCounter++
+	c.prStream <- &models.PageReportMessage{
+		PageReport: pageReport,
+		HtmlNode:   &html.Node{},
+		Header:     &http.Header{},
+		Crawled:    c.responseCounter,
+		Discovered: c.queue.Count(),
+	}
+}
+
+// Returns the certificate of the URL's host if it is an https URL. The first time a host's
+// certificate is found, it is sent through the prStream channel so it can be stored.
+func (c *Crawler) certificate(u *url.URL) *models.TLSCertificate {
+	if u.Scheme != "https" {
+		return nil
+	}
+
+	certificate := c.certificates.Get(u.Hostname())
+	if certificate == nil || c.sentHosts[certificate.Host] {
+		return certificate
+	}
+
+	c.sentHosts[certificate.Host] = true
+	c.prStream <- &models.PageReportMessage{
+		Certificate: certificate,
+		Crawled:     c.responseCounter,
+		Discovered:  c.queue.Count(),
+	}
+
+	return certificate
+}
+
+// Sends the robots.txt file of the URL's host through the prStream channel the first time
+// the host is found so it can be stored.
+func (c *Crawler) robotstxt(u *url.URL) {
+	if c.sentRobots[u.Host] {
+		return
+	}
+
+	c.sentRobots[u.Host] = true
+
+	file := c.robotsChecker.File(u)
+	if file == nil {
+		return
+	}
+
+	c.prStream <- &models.PageReportMessage{
+		Robotstxt:  file,
+		Crawled:    c.responseCounter,
+		Discovered: c.queue.Count(),
+	}
 }
 
 // Returns true if the crawler is allowed to crawl the domain, checking the allowedDomains slice.
@@ -253,6 +786,22 @@ func (c *Crawler) domainIsAllowed(s string) bool {
 	return false
 }
 
+// Returns true if the redirect location u can be requested to record the redirect chain.
+// The locations in domains that are not allowed, excluded by the URL rules or blocked by
+// robots.txt are not requested.
+func (c *Crawler) followRedirect(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil || !c.domainIsAllowed(parsed.Host) {
+		return false
+	}
+
+	if !c.options.Filter.Allowed(parsed) {
+		return false
+	}
+
+	return c.options.IgnoreRobotsTxt || !c.robotsChecker.IsBlocked(parsed)
+}
+
 // Callback to load sitemap URLs into the sitemap storage
 func (c *Crawler) loadSitemapURLs(u string) {
 	l, err := url.Parse(u)
@@ -264,19 +813,73 @@ func (c *Crawler) loadSitemapURLs(u string) {
 		l.Path = "/"
 	}
 
-	c.sitemapStorage.Add(l.String())
+	c.sitemapStorage.Add(c.normalize(l).String())
+}
+
+// Returns the normalized URL. The first time a variant of a normalized URL is found it is
+// sent through the prStream channel so it can be stored. Variants are also added to the
+// URL storage so they are only sent once, even if the crawl is resumed.
+func (c *Crawler) normalize(u *url.URL) *url.URL {
+	n := c.options.Normalizer.Normalize(u)
+
+	variant := u.String()
+	if n.String() == variant || c.storage.Seen(variant) {
+		return n
+	}
+
+	c.storage.Add(variant)
+	c.prStream <- &models.PageReportMessage{
+		Variant:    &models.URLVariant{URL: n.String(), Variant: variant},
+		Crawled:    c.responseCounter,
+		Discovered: c.queue.Count(),
+	}
+
+	return n
+}
+
+// Returns the normalized URL of the string s, or s if it is not a valid URL.
+func (c *Crawler) normalizeString(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+
+	return c.normalize(u).String()
 }
 
 // queueSitemapURLs loops through the sitemap's URLs, adding any unseen URLs to the crawler's queue.
 func (c *Crawler) queueSitemapURLs() {
 	c.sitemapStorage.Iterate(func(v string) {
-		if !c.storage.Seen(v) {
-			c.storage.Add(v)
-			c.queue.Push(&httpcrawler.RequestMessage{URL: v})
+		if c.storage.Seen(v) {
+			return
+		}
+
+		c.storage.Add(v)
+
+		if u, err := url.Parse(v); err == nil && c.excluded(u) {
+			return
 		}
+
+		c.queue.Push(&httpcrawler.RequestMessage{URL: v})
 	})
 }
 
+// Returns true if the URL is not allowed by the crawler's filter.
+// Excluded URLs are sent through the prStream channel so they can be counted.
+func (c *Crawler) excluded(u *url.URL) bool {
+	if c.options.Filter.Allowed(u) {
+		return false
+	}
+
+	c.prStream <- &models.PageReportMessage{
+		Excluded:   u.String(),
+		Crawled:    c.responseCounter,
+		Discovered: c.queue.Count(),
+	}
+
+	return true
+}
+
 // Returns true if the sitemap.xml file exists
 func (c *Crawler) SitemapExists() bool {
 	return c.sitemapExists
@@ -287,6 +890,11 @@ func (c *Crawler) RobotstxtExists() bool {
 	return c.robotstxtExists
 }
 
+// Returns the start URL as it is requested by the crawler, once it has been normalized.
+func (c *Crawler) StartURL() string {
+	return c.url.String()
+}
+
 // Returns a slice with all the crawlable Links from the PageReport's links.
 // URLs extracted from internal Links and ExternalLinks are crawlable only if the domain name is allowed and
 // if they don't have the "nofollow" attribute. If they have the "nofollow" attribute, they are also considered
@@ -1,20 +1,29 @@
 package crawler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/stjudewashere/seonaut/internal/cache_manager"
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/project"
 	"github.com/stjudewashere/seonaut/internal/pubsub"
 	"github.com/stjudewashere/seonaut/internal/report_manager"
+	"github.com/stjudewashere/seonaut/internal/urlfilter"
+	"github.com/stjudewashere/seonaut/internal/urlnormalizer"
 )
 
 const (
-	// Max number of page reports that will be created
-	MaxPageReports = 20000
+	// Default server-wide crawl limits, used if they are not set in the config.
+	// The page reports limit is the projects' default one.
+	MaxThreads = 5
+	MaxTimeout = 60
 
 	// Max number returned by GetLastCrawls
 	LastCrawlsLimit = 5
@@ -22,116 +31,318 @@ const (
 
 // CrawlerConfig stores the configuration for the crawler.
 // It is loaded from the config package.
+// The limits are server-wide caps for the crawl settings of each project.
 type Config struct {
-	Agent string `mapstructure:"agent"`
+	Agent          string `mapstructure:"agent"`
+	MaxPageReports int    `mapstructure:"max_pagereports"`
+	MaxThreads     int    `mapstructure:"max_threads"`
+	MinDelay       int    `mapstructure:"min_delay"`    // Milliseconds
+	MaxTimeout     int    `mapstructure:"max_timeout"`  // Seconds
+	Retries        int    `mapstructure:"retries"`      // Retries of requests that fail with a network error or a 5xx status code
+	RetryDelay     int    `mapstructure:"retry_delay"`  // Milliseconds, doubled in each retry
+	ChromeURL      string `mapstructure:"chrome_url"`   // DevTools endpoint of the headless browser used to render JavaScript
+	Proxy          string `mapstructure:"proxy"`        // Proxy used by the crawls of the projects without a proxy
+	MobileAgent    string `mapstructure:"mobile_agent"` // User agent of the mobile crawls
 }
 
+var (
+	// ErrCrawlInProgress is returned when starting a crawler for a project that is already being crawled.
+	ErrCrawlInProgress = errors.New("project is already being crawled")
+
+	// ErrNoCrawlInProgress is returned when trying to control the crawler of a project that is not being crawled.
+	ErrNoCrawlInProgress = errors.New("project is not being crawled")
+)
+
 type Storage interface {
-	SaveCrawl(models.Project) (*models.Crawl, error)
+	SaveCrawl(models.Project, bool) (*models.Crawl, error)
 	SavePageReport(*models.PageReport, int64) (*models.PageReport, error)
 	SaveEndCrawl(*models.Crawl) (*models.Crawl, error)
 	GetLastCrawls(models.Project, int) []models.Crawl
-	GetPreviousCrawl(*models.Project) (*models.Crawl, error)
+	GetExpiredCrawls(*models.Project, int) []models.Crawl
 	DeleteCrawlData(c *models.Crawl)
+	SaveCrawlFrontier(*models.Crawl, *models.Frontier) error
+	GetCrawlFrontier(*models.Crawl) (*models.Frontier, error)
+	DeleteCrawlFrontier(*models.Crawl)
+	GetResumableCrawls() []models.Crawl
+	FindCrawlProject(*models.Crawl) (models.Project, error)
+	DeletePageReportsAfter(*models.Crawl, int64) error
+	CountCrawlTotals(*models.Crawl) error
+	SaveURLVariant(*models.Crawl, *models.URLVariant) error
+	SaveTLSCertificate(*models.Crawl, *models.TLSCertificate) error
+	SaveSitemapFile(*models.Crawl, *models.SitemapFile) error
+	SaveRobotstxtFile(*models.Crawl, *models.RobotstxtFile) error
+	SaveMobileCrawl(models.Project, *models.Crawl) (*models.Crawl, error)
+	GetCrawledURLs(*models.Crawl) []string
+	GetExternalLinkURLs(*models.Crawl, int) []string
+	SaveExternalLinkStatus(*models.Crawl, *models.ExternalLinkStatus) error
+}
+
+// IssueService stores the issue count once the crawl's issues have been created.
+type IssueService interface {
+	SaveCrawlIssuesCount(*models.Crawl)
 }
+
 type Service struct {
 	store         Storage
 	broker        *pubsub.Broker
 	config        *Config
 	cacheManager  *cache_manager.CacheManager
 	reportManager *report_manager.ReportManager
+	issueService  IssueService
+	crawlers      map[int64]*Crawler
+	lock          *sync.Mutex
 }
 
-func NewService(s Storage, broker *pubsub.Broker, c *Config, cm *cache_manager.CacheManager, rm *report_manager.ReportManager) *Service {
+func NewService(s Storage, broker *pubsub.Broker, c *Config, cm *cache_manager.CacheManager, rm *report_manager.ReportManager, is IssueService) *Service {
+	if c.MaxPageReports < 1 {
+		c.MaxPageReports = project.DefaultMaxPageReports
+	}
+
+	if c.MaxThreads < 1 {
+		c.MaxThreads = MaxThreads
+	}
+
+	if c.MaxTimeout < 1 {
+		c.MaxTimeout = MaxTimeout
+	}
+
 	return &Service{
 		store:         s,
 		broker:        broker,
 		config:        c,
 		cacheManager:  cm,
 		reportManager: rm,
+		issueService:  is,
+		crawlers:      make(map[int64]*Crawler),
+		lock:          &sync.Mutex{},
 	}
 }
 
-// StartCrawler creates a new crawler and crawls the project's URL
+// StartCrawler creates a new crawler and crawls the project's URL.
+// Once the crawl has ended, or it has been cancelled, it creates the multipage issues
+// and stores the issue count. It returns ErrCrawlInProgress if the project is already
+// being crawled.
 func (s *Service) StartCrawler(p models.Project) (*models.Crawl, error) {
+	return s.start(p, nil, false)
+}
+
+// StartListCrawler creates a new crawler in list mode, which crawls only the URLs in
+// the list without following their links. The URLs go through the same parsing and
+// issue reporting as in a regular crawl.
+func (s *Service) StartListCrawler(p models.Project, urls []string) (*models.Crawl, error) {
+	return s.start(p, urls, true)
+}
+
+// Starts a new crawl of the project. In list mode only the URLs in the urls slice are crawled.
+func (s *Service) start(p models.Project, urls []string, listMode bool) (*models.Crawl, error) {
+	if !s.setCrawling(p.Id) {
+		return nil, ErrCrawlInProgress
+	}
+	defer s.unsetCrawling(p.Id)
+
 	u, err := url.Parse(p.URL)
 	if err != nil {
 		return nil, err
 	}
 
-	if u.Path == "" {
-		u.Path = "/"
+	if _, err := s.proxy(p); err != nil {
+		return nil, err
 	}
 
-	options := &Options{
-		MaxPageReports:  MaxPageReports,
-		IgnoreRobotsTxt: p.IgnoreRobotsTxt,
-		FollowNofollow:  p.FollowNofollow,
-		IncludeNoindex:  p.IncludeNoindex,
-		UserAgent:       s.config.Agent,
-		CrawlSitemap:    p.CrawlSitemap,
-		AllowSubdomains: p.AllowSubdomains,
-		BasicAuth:       p.BasicAuth,
-		AuthUser:        p.AuthUser,
-		AuthPass:        p.AuthPass,
+	crawl, err := s.store.SaveCrawl(p, listMode)
+	if err != nil {
+		return nil, err
 	}
 
-	crawl, err := s.store.SaveCrawl(p)
+	return s.crawl(p, u, crawl, nil, urls)
+}
+
+// ResumeUnfinishedCrawls resumes the crawls that were interrupted, for instance
+// by a server restart, from their last frontier checkpoint. It returns the
+// number of crawls being resumed.
+func (s *Service) ResumeUnfinishedCrawls() int {
+	crawls := s.store.GetResumableCrawls()
+	for _, crawl := range crawls {
+		p, err := s.store.FindCrawlProject(&crawl)
+		if err != nil {
+			log.Printf("ResumeUnfinishedCrawls: crawl %d: %v\n", crawl.Id, err)
+			continue
+		}
+
+		if !s.setCrawling(p.Id) {
+			continue
+		}
+
+		go func(p models.Project, crawl models.Crawl) {
+			defer s.unsetCrawling(p.Id)
+
+			log.Printf("Resuming crawl %s\n", p.URL)
+			c, err := s.resumeCrawl(p, &crawl)
+			if err != nil {
+				log.Printf("ResumeCrawl: %s %v\n", p.URL, err)
+				return
+			}
+
+			log.Printf("Crawled %d pages at %s\n", c.TotalURLs, p.URL)
+		}(p, crawl)
+	}
+
+	return len(crawls)
+}
+
+// Resumes the crawl from its last frontier checkpoint. The page reports saved after the
+// checkpoint are removed, as their URLs are still in the frontier and will be crawled again,
+// and the crawl's counters are recomputed from the stored page reports.
+func (s *Service) resumeCrawl(p models.Project, crawl *models.Crawl) (*models.Crawl, error) {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	frontier, err := s.store.GetCrawlFrontier(crawl)
 	if err != nil {
 		return nil, err
 	}
 
+	err = s.store.DeletePageReportsAfter(crawl, frontier.PageReportId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.CountCrawlTotals(crawl)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.crawl(p, u, crawl, frontier, nil)
+}
+
+// Crawls the project's URL u, storing the page reports in the crawl. If frontier is not nil
+// the crawl is resumed from the frontier checkpoint. In list mode the urls are crawled instead
+// of the project's URL. Once the crawl has ended it creates the multipage issues and stores
+// the issue count.
+func (s *Service) crawl(p models.Project, u *url.URL, crawl *models.Crawl, frontier *models.Frontier, urls []string) (*models.Crawl, error) {
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	options, err := s.crawlOptions(p, crawl, frontier, urls, s.config.Agent)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastPageReportId int64
+	if frontier != nil {
+		lastPageReportId = frontier.PageReportId
+	}
+
 	c := NewCrawler(u, options)
+	s.setCrawler(p.Id, c)
+
+	// The multipage reporters compare the page reports with the normalized start URL.
+	crawl.URL = c.StartURL()
+
+	// URLs seen by the crawler that are not stored in the frontier yet.
+	var seen []string
 
 	for r := range c.Stream() {
-		// URLs are added to the TotalURLs count if they are not blocked
-		// by the robots.txt and they are indexable.
-		// Otherwise they are added to the BlockedByRobotstxt or Noindex count.
-		if r.PageReport.BlockedByRobotstxt {
-			crawl.BlockedByRobotstxt++
-		} else if r.PageReport.Noindex {
-			crawl.Noindex++
-		} else {
-			crawl.TotalURLs++
+		// The frontier checkpoints are not stored for projects with basic authentication, a login
+		// URL or proxy authentication because the crawl can't be resumed without the user's credentials.
+		if r.Frontier != nil {
+			if p.BasicAuth || p.LoginURL != "" || p.ProxyAuth {
+				continue
+			}
+
+			seen = append(seen, r.Frontier.Seen...)
+			r.Frontier.Seen = seen
+			r.Frontier.PageReportId = lastPageReportId
+			if err := s.store.SaveCrawlFrontier(crawl, r.Frontier); err != nil {
+				log.Printf("SaveCrawlFrontier: %v\n", err)
+				continue
+			}
+
+			seen = nil
+			continue
+		}
+
+		if r.Excluded != "" {
+			crawl.ExcludedByRules++
+			continue
 		}
 
-		// Count total internal follow and nofollow links.
-		for _, l := range r.PageReport.Links {
-			if l.NoFollow {
-				crawl.InternalNoFollowLinks++
-			} else {
-				crawl.InternalFollowLinks++
+		if r.Variant != nil {
+			if err := s.store.SaveURLVariant(crawl, r.Variant); err != nil {
+				log.Printf("SaveURLVariant: %v\n", err)
 			}
+
+			continue
 		}
 
-		// Count total external follow, nofollow, sponsored and UGC links.
-		for _, l := range r.PageReport.ExternalLinks {
-			if l.NoFollow {
-				crawl.ExternalNoFollowLinks++
-			} else {
-				crawl.ExternalFollowLinks++
+		if r.Certificate != nil {
+			if err := s.store.SaveTLSCertificate(crawl, r.Certificate); err != nil {
+				log.Printf("SaveTLSCertificate: %v\n", err)
 			}
 
-			if l.Sponsored {
-				crawl.SponsoredLinks++
+			continue
+		}
+
+		if r.Sitemap != nil {
+			if err := s.store.SaveSitemapFile(crawl, r.Sitemap); err != nil {
+				log.Printf("SaveSitemapFile: %v\n", err)
 			}
 
-			if l.UGC {
-				crawl.UGCLinks++
+			continue
+		}
+
+		if r.Robotstxt != nil {
+			if err := s.store.SaveRobotstxtFile(crawl, r.Robotstxt); err != nil {
+				log.Printf("SaveRobotstxtFile: %v\n", err)
 			}
+
+			continue
 		}
 
-		r.PageReport, err = s.store.SavePageReport(r.PageReport, crawl.Id)
+		countPageReport(crawl, r.PageReport)
+
+		pageReport, err := s.store.SavePageReport(r.PageReport, crawl.Id)
 		if err != nil {
 			log.Printf("SavePageReport: %v\n", err)
 			continue
 		}
 
+		r.PageReport = pageReport
+		lastPageReportId = r.PageReport.Id
+
 		s.reportManager.CreatePageIssues(r.PageReport, r.HtmlNode, r.Header, crawl)
 
 		s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "PageReport", Data: r})
 	}
 
+	s.store.DeleteCrawlFrontier(crawl)
+
+	// The external links are checked before creating the multipage issues,
+	// which include the issues of the pages linking to broken external URLs.
+	// The crawler is still registered so the check can be cancelled or paused.
+	if p.CheckLinks && !c.Stopped() {
+		s.checkExternalLinks(p, crawl, c)
+	}
+
+	// The mobile crawl runs before the crawl ends so its progress is streamed to the
+	// live crawl. Its own crawler can be cancelled without cancelling the crawl.
+	if p.MobileCrawl && !c.Stopped() {
+		if _, err := s.mobileCrawl(p, u, crawl); err != nil {
+			log.Printf("Mobile crawl %s: %v\n", p.URL, err)
+		}
+	}
+
+	// The crawler can't be cancelled or paused once the links have been checked.
+	s.setCrawler(p.Id, nil)
+
+	status := models.CrawlCompleted
+	if c.Stopped() {
+		status = models.CrawlCancelled
+	}
+
 	crawl.RobotstxtExists = c.RobotstxtExists()
 	crawl.SitemapExists = c.SitemapExists()
 
@@ -140,18 +351,349 @@ func (s *Service) StartCrawler(p models.Project) (*models.Crawl, error) {
 		return nil, err
 	}
 
+	// Remove the data of the crawls that exceed the number of crawls the project keeps.
 	go func() {
-		previous, err := s.store.GetPreviousCrawl(&p)
+		keep := max(p.KeepCrawls, 1)
+		for _, expired := range s.store.GetExpiredCrawls(&p, keep) {
+			s.store.DeleteCrawlData(&expired)
+			s.cacheManager.RemoveCrawlCache(&expired)
+		}
+	}()
+
+	s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "IssuesInit"})
+	s.reportManager.CreateMultipageIssues(crawl)
+	s.issueService.SaveCrawlIssuesCount(crawl)
+	s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{
+		Name: "CrawlEnd",
+		Data: &models.CrawlEndMessage{TotalURLs: crawl.TotalURLs, Status: status},
+	})
+
+	return crawl, nil
+}
+
+// Crawls the project's URL u again with the mobile user agent, following the links as in the
+// desktop crawl. If the desktop crawl is in list mode, the URLs it crawled are crawled instead.
+// The page reports are stored in a mobile crawl so they can be compared with the desktop ones,
+// and they are published so the progress is shown in the live crawl.
+// Issues are not created for the mobile crawl.
+func (s *Service) mobileCrawl(p models.Project, u *url.URL, desktop *models.Crawl) (*models.Crawl, error) {
+	var urls []string
+	if desktop.ListMode {
+		urls = s.store.GetCrawledURLs(desktop)
+		if len(urls) == 0 {
+			return nil, errors.New("no URLs to crawl")
+		}
+	}
+
+	crawl, err := s.store.SaveMobileCrawl(p, desktop)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.crawlOptions(p, crawl, nil, urls, s.config.MobileAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewCrawler(u, options)
+	s.setCrawler(p.Id, c)
+	s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "MobileCrawl"})
+
+	for r := range c.Stream() {
+		// Frontier checkpoints, excluded URLs, URL variants, certificates, sitemaps and
+		// robots.txt files are not stored in mobile crawls.
+		if r.PageReport == nil {
+			continue
+		}
+
+		countPageReport(crawl, r.PageReport)
+
+		pageReport, err := s.store.SavePageReport(r.PageReport, crawl.Id)
 		if err != nil {
-			log.Printf("Crawler: PreviousCrawl: %v\n", err)
-			return
+			log.Printf("SavePageReport: %v\n", err)
+			continue
 		}
 
-		s.store.DeleteCrawlData(previous)
-		s.cacheManager.RemoveCrawlCache(previous)
+		r.PageReport = pageReport
+		s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "PageReport", Data: r})
+	}
+
+	crawl.RobotstxtExists = c.RobotstxtExists()
+	crawl.SitemapExists = c.SitemapExists()
+
+	return s.store.SaveEndCrawl(crawl)
+}
+
+// Requests the unique external URLs found in the crawl and stores their status. The number of
+// checked URLs is limited by the project's max number of page reports. The check is cancelled
+// if the crawler is stopped and it waits while the crawler is paused.
+func (s *Service) checkExternalLinks(p models.Project, crawl *models.Crawl, c *Crawler) {
+	urls := s.store.GetExternalLinkURLs(crawl, clamp(p.MaxPageReports, 1, s.config.MaxPageReports))
+	if len(urls) == 0 {
+		return
+	}
+
+	proxy, err := s.proxy(p)
+	if err != nil {
+		log.Printf("Crawl %s external links: %v\n", p.URL, err)
+		return
+	}
+
+	client := httpcrawler.NewClient(&httpcrawler.ClientOptions{
+		UserAgent: s.config.Agent,
+		Timeout:   time.Duration(clamp(p.CrawlTimeout, 1, s.config.MaxTimeout)) * time.Second,
+		Proxy:     proxy,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-c.stop:
+			cancel()
+		case <-ctx.Done():
+		}
 	}()
 
-	return crawl, nil
+	checked := 0
+	checker := httpcrawler.NewLinkChecker(client, clamp(p.CrawlThreads, 1, s.config.MaxThreads))
+	for status := range checker.Check(ctx, urls) {
+		// The statuses are not received while the crawler is paused, so the
+		// checker stops once the requests in progress are completed.
+		if resume := c.resumeChan(); resume != nil {
+			select {
+			case <-resume:
+			case <-ctx.Done():
+			}
+		}
+
+		if err := s.store.SaveExternalLinkStatus(crawl, status); err != nil {
+			log.Printf("SaveExternalLinkStatus: %v\n", err)
+		}
+
+		checked++
+		s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{
+			Name: "LinkCheck",
+			Data: &models.LinkCheckMessage{Checked: checked, Total: len(urls)},
+		})
+	}
+}
+
+// Returns the proxy used in the project's crawls. The project's proxy overrides the server's proxy.
+// It returns an error if the proxy is not valid, so the requests are never sent without the proxy.
+func (s *Service) proxy(p models.Project) (*url.URL, error) {
+	proxyURL := p.ProxyURL
+	if proxyURL == "" {
+		proxyURL = s.config.Proxy
+	}
+
+	return httpcrawler.ParseProxy(proxyURL)
+}
+
+// Returns the crawler options for the project's crawl using the user agent.
+// It returns an error if the crawl's proxy is not valid.
+func (s *Service) crawlOptions(p models.Project, crawl *models.Crawl, frontier *models.Frontier, urls []string, agent string) (*Options, error) {
+	proxy, err := s.proxy(p)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := httpcrawler.ParseHeaders(p.CrawlHeaders)
+	if err != nil {
+		log.Printf("Crawl %s headers: %v\n", p.URL, err)
+	}
+
+	loginData, err := url.ParseQuery(p.LoginData)
+	if err != nil {
+		log.Printf("Crawl %s login data: %v\n", p.URL, err)
+	}
+
+	filter, err := urlfilter.New(p.IncludeRules, p.ExcludeRules)
+	if err != nil {
+		log.Printf("Crawl %s URL rules: %v\n", p.URL, err)
+	}
+
+	var normalizer *urlnormalizer.Normalizer
+	if p.NormalizeURLs {
+		normalizer = urlnormalizer.New(urlnormalizer.ParseParams(p.StripParams), p.IgnoreParams)
+	}
+
+	options := &Options{
+		MaxPageReports:  clamp(p.MaxPageReports, 1, s.config.MaxPageReports),
+		MaxDepth:        max(p.MaxDepth, 0),
+		Threads:         clamp(p.CrawlThreads, 1, s.config.MaxThreads),
+		Delay:           time.Duration(max(p.CrawlDelay, s.config.MinDelay)) * time.Millisecond,
+		Timeout:         time.Duration(clamp(p.CrawlTimeout, 1, s.config.MaxTimeout)) * time.Second,
+		Retries:         s.config.Retries,
+		RetryDelay:      time.Duration(s.config.RetryDelay) * time.Millisecond,
+		IgnoreRobotsTxt: p.IgnoreRobotsTxt,
+		FollowNofollow:  p.FollowNofollow,
+		IncludeNoindex:  p.IncludeNoindex,
+		UserAgent:       agent,
+		CrawlSitemap:    p.CrawlSitemap && !crawl.ListMode,
+		AllowSubdomains: p.AllowSubdomains,
+		BasicAuth:       p.BasicAuth,
+		AuthUser:        p.AuthUser,
+		AuthPass:        p.AuthPass,
+		Headers:         headers,
+		UseCookies:      p.UseCookies,
+		LoginURL:        p.LoginURL,
+		LoginData:       loginData,
+		Proxy:           proxy,
+		Frontier:        frontier,
+		ListMode:        crawl.ListMode,
+		URLs:            urls,
+		Filter:          filter,
+		Normalizer:      normalizer,
+		RobotsDraft:     p.RobotsDraft,
+		RobotsToken:     p.RobotsToken,
+	}
+
+	// The headless browser doesn't use the project's proxy, so the pages of projects
+	// with their own proxy are never rendered.
+	if p.RenderJS && s.config.ChromeURL != "" && p.ProxyURL == "" {
+		options.Renderer = httpcrawler.NewChromeRenderer(s.config.ChromeURL, agent, 0)
+	}
+
+	return options, nil
+}
+
+// Adds the page report to the crawl's URL and link counters.
+func countPageReport(crawl *models.Crawl, pageReport *models.PageReport) {
+	// URLs are added to the TotalURLs count if they are not blocked
+	// by the robots.txt and they are indexable.
+	// Otherwise they are added to the BlockedByRobotstxt or Noindex count.
+	if pageReport.BlockedByRobotstxt {
+		crawl.BlockedByRobotstxt++
+	} else if pageReport.Noindex {
+		crawl.Noindex++
+	} else {
+		crawl.TotalURLs++
+	}
+
+	// Count total internal follow and nofollow links.
+	for _, l := range pageReport.Links {
+		if l.NoFollow {
+			crawl.InternalNoFollowLinks++
+		} else {
+			crawl.InternalFollowLinks++
+		}
+	}
+
+	// Count total external follow, nofollow, sponsored and UGC links.
+	for _, l := range pageReport.ExternalLinks {
+		if l.NoFollow {
+			crawl.ExternalNoFollowLinks++
+		} else {
+			crawl.ExternalFollowLinks++
+		}
+
+		if l.Sponsored {
+			crawl.SponsoredLinks++
+		}
+
+		if l.UGC {
+			crawl.UGCLinks++
+		}
+	}
+}
+
+// CancelCrawler stops the project's crawler. The crawl ends with the URLs
+// crawled so far and its report is created as usual.
+func (s *Service) CancelCrawler(p models.Project) error {
+	c, err := s.crawler(p.Id)
+	if err != nil {
+		return err
+	}
+
+	c.Stop()
+
+	return nil
+}
+
+// PauseCrawler pauses the project's crawler until ResumeCrawler is called.
+func (s *Service) PauseCrawler(p models.Project) error {
+	c, err := s.crawler(p.Id)
+	if err != nil {
+		return err
+	}
+
+	c.Pause()
+	s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "CrawlPaused"})
+
+	return nil
+}
+
+// ResumeCrawler resumes the project's paused crawler.
+func (s *Service) ResumeCrawler(p models.Project) error {
+	c, err := s.crawler(p.Id)
+	if err != nil {
+		return err
+	}
+
+	c.Resume()
+	s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "CrawlResumed"})
+
+	return nil
+}
+
+// CrawlerPaused returns true if the project's crawler is paused.
+func (s *Service) CrawlerPaused(p models.Project) bool {
+	c, err := s.crawler(p.Id)
+	if err != nil {
+		return false
+	}
+
+	return c.Paused()
+}
+
+// Returns the project's running crawler.
+// It returns ErrNoCrawlInProgress if the project is not being crawled.
+func (s *Service) crawler(pid int64) (*Crawler, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c := s.crawlers[pid]
+	if c == nil {
+		return nil, ErrNoCrawlInProgress
+	}
+
+	return c, nil
+}
+
+// Marks the project as being crawled. It returns false if it was already being crawled.
+func (s *Service) setCrawling(pid int64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.crawlers[pid]; ok {
+		return false
+	}
+
+	s.crawlers[pid] = nil
+
+	return true
+}
+
+// Sets the project's running crawler so it can be cancelled, paused and resumed.
+func (s *Service) setCrawler(pid int64, c *Crawler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.crawlers[pid] = c
+}
+
+func (s *Service) unsetCrawling(pid int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.crawlers, pid)
+}
+
+// Returns n limited to the range between lo and hi.
+func clamp(n, lo, hi int) int {
+	return min(max(n, lo), hi)
 }
 
 // Get a slice with 'LastCrawlsLimit' number of the crawls
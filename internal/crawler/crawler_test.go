@@ -0,0 +1,28 @@
+package crawler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+	"github.com/stjudewashere/seonaut/internal/urlnormalizer"
+)
+
+func TestCrawlerStartURL(t *testing.T) {
+	table := []struct {
+		url        string
+		normalizer *urlnormalizer.Normalizer
+		expected   string
+	}{
+		{"https://example.com", nil, "https://example.com/"},
+		{"https://Example.com:443/index.html", urlnormalizer.New(nil, false), "https://example.com/"},
+	}
+
+	for _, tc := range table {
+		u, _ := url.Parse(tc.url)
+		c := crawler.NewCrawler(u, &crawler.Options{Normalizer: tc.normalizer})
+		if startURL := c.StartURL(); startURL != tc.expected {
+			t.Errorf("StartURL %s: %s want %s", tc.url, startURL, tc.expected)
+		}
+	}
+}
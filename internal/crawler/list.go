@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// ParseURLList returns the URLs of a list to be crawled in list mode. The list can have
+// one URL per line, or be a CSV or TSV file with the URL in any of its columns.
+// Lines without an http or https URL, such as CSV headers, are skipped. The URLs that are
+// not in the project's domain, or its subdomains if they are allowed, are returned as ignored.
+func ParseURLList(r io.Reader, p models.Project) (urls []string, ignored []string, err error) {
+	projectURL, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	domain := strings.TrimPrefix(projectURL.Host, "www.")
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		u := lineURL(scanner.Text())
+		if u == nil {
+			continue
+		}
+
+		host := strings.TrimPrefix(u.Host, "www.")
+		if host != domain && !(p.AllowSubdomains && strings.HasSuffix(host, "."+domain)) {
+			ignored = append(ignored, u.String())
+			continue
+		}
+
+		if u.Path == "" {
+			u.Path = "/"
+		}
+
+		u.Fragment = ""
+		urls = append(urls, u.String())
+	}
+
+	return urls, ignored, scanner.Err()
+}
+
+// Returns the first http or https URL in the line. The line is parsed as a CSV record
+// separated by tabs, semicolons or commas, so URLs with commas must be quoted.
+func lineURL(line string) *url.URL {
+	r := csv.NewReader(strings.NewReader(line))
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+	for _, sep := range []rune{'\t', ';', ','} {
+		if strings.ContainsRune(line, sep) {
+			r.Comma = sep
+			break
+		}
+	}
+
+	fields, err := r.Read()
+	if err != nil {
+		return nil
+	}
+
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if !strings.HasPrefix(f, "http://") && !strings.HasPrefix(f, "https://") {
+			continue
+		}
+
+		u, err := url.Parse(f)
+		if err != nil || u.Host == "" {
+			continue
+		}
+
+		return u
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package crawler_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+func TestParseURLList(t *testing.T) {
+	list := strings.Join([]string{
+		"URL,Status",
+		"https://example.com/a,200",
+		"\"https://example.com/b?x=1,2\";301",
+		"",
+		"https://www.example.com\tlanding",
+		"https://blog.example.com/post",
+		"https://example.org/",
+		"not an url",
+		"https://example.com/c#fragment",
+	}, "\n")
+
+	p := models.Project{URL: "https://example.com"}
+	urls, ignored, err := crawler.ParseURLList(strings.NewReader(list), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"https://example.com/a",
+		"https://example.com/b?x=1,2",
+		"https://www.example.com/",
+		"https://example.com/c",
+	}
+
+	if strings.Join(urls, " ") != strings.Join(expected, " ") {
+		t.Errorf("urls %v != %v", urls, expected)
+	}
+
+	if len(ignored) != 2 {
+		t.Errorf("ignored %v", ignored)
+	}
+
+	p.AllowSubdomains = true
+	urls, _, _ = crawler.ParseURLList(strings.NewReader(list), p)
+	if len(urls) != 5 {
+		t.Errorf("urls with subdomains %v", urls)
+	}
+}
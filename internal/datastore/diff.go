@@ -0,0 +1,239 @@
+package datastore
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/diff"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SQL expressions of the page report fields that can be compared between crawls.
+// The %[1]s verb is replaced with the alias of the pagereports table.
+var diffColumns = map[string]string{
+	diff.StatusCode:  "%[1]s.status_code",
+	diff.Title:       "%[1]s.title",
+	diff.Description: "%[1]s.description",
+	diff.Canonical:   "%[1]s.canonical",
+	diff.Robots:      "%[1]s.robots",
+	diff.Words:       "%[1]s.words",
+	diff.Links:       "(SELECT count(*) FROM links WHERE links.pagereport_id = %[1]s.id)",
+	diff.Hreflang: `(SELECT GROUP_CONCAT(CONCAT(to_lang, ' ', to_url) ORDER BY to_lang, to_url SEPARATOR ', ')
+		FROM hreflangs WHERE hreflangs.pagereport_id = %[1]s.id)`,
+}
+
+// Returns the SQL expressions of the field for the pagereports aliased as a and b.
+func diffColumn(field string) (a, b string, ok bool) {
+	column, ok := diffColumns[field]
+	if !ok {
+		return "", "", false
+	}
+
+	return fmt.Sprintf(column, "a"), fmt.Sprintf(column, "b"), true
+}
+
+// CountNewPageReports returns the number of crawled page reports in the crawl with id cid
+// that can't be found in the crawl with id prevCid.
+func (ds *Datastore) CountNewPageReports(cid, prevCid int64) int {
+	query := `
+		SELECT count(*)
+		FROM pagereports AS a
+		WHERE a.crawl_id = ? AND a.crawled = 1 AND NOT EXISTS (
+			SELECT 1 FROM pagereports AS b
+			WHERE b.crawl_id = ? AND b.url_hash = a.url_hash AND b.crawled = 1
+		)`
+
+	var c int
+	row := ds.db.QueryRow(query, cid, prevCid)
+	if err := row.Scan(&c); err != nil {
+		log.Printf("CountNewPageReports: %v\n", err)
+	}
+
+	return c
+}
+
+// CountPageReportChanges returns the number of page reports with a different value
+// in the field column in the crawls with ids cid and prevCid.
+func (ds *Datastore) CountPageReportChanges(cid, prevCid int64, field string) int {
+	a, b, ok := diffColumn(field)
+	if !ok {
+		log.Printf("CountPageReportChanges: field %s not supported\n", field)
+		return 0
+	}
+
+	query := fmt.Sprintf(`
+		SELECT count(*)
+		FROM pagereports AS a
+		INNER JOIN pagereports AS b ON b.url_hash = a.url_hash AND b.crawl_id = ? AND b.crawled = 1
+		WHERE a.crawl_id = ? AND a.crawled = 1 AND NOT (%s <=> %s)`, a, b)
+
+	var c int
+	row := ds.db.QueryRow(query, prevCid, cid)
+	if err := row.Scan(&c); err != nil {
+		log.Printf("CountPageReportChanges: %v\n", err)
+	}
+
+	return c
+}
+
+// CountNewIssues returns a map with the number of page reports by issue type that
+// have the issue in the crawl with id cid but didn't have it in the crawl with id prevCid.
+func (ds *Datastore) CountNewIssues(cid, prevCid int64) map[string]int {
+	query := `
+		SELECT
+			issue_types.type,
+			count(DISTINCT a.pagereport_id)
+		FROM issues AS a
+		INNER JOIN issue_types ON issue_types.id = a.issue_type_id
+		INNER JOIN pagereports AS pa ON pa.id = a.pagereport_id
+		WHERE a.crawl_id = ? AND NOT EXISTS (
+			SELECT 1 FROM issues AS b
+			INNER JOIN pagereports AS pb ON pb.id = b.pagereport_id
+			WHERE b.crawl_id = ? AND b.issue_type_id = a.issue_type_id AND pb.url_hash = pa.url_hash
+		)
+		GROUP BY issue_types.type`
+
+	m := make(map[string]int)
+	rows, err := ds.db.Query(query, cid, prevCid)
+	if err != nil {
+		log.Printf("CountNewIssues: %v\n", err)
+		return m
+	}
+
+	for rows.Next() {
+		var k string
+		var v int
+		if err := rows.Scan(&k, &v); err != nil {
+			log.Printf("CountNewIssues: %v\n", err)
+			continue
+		}
+
+		m[k] = v
+	}
+
+	return m
+}
+
+// FindNewPageReports sends through a read-only channel the crawled page reports in the
+// crawl with id cid that can't be found in the crawl with id prevCid.
+func (ds *Datastore) FindNewPageReports(cid, prevCid int64) <-chan *models.PageReport {
+	prStream := make(chan *models.PageReport)
+
+	go func() {
+		defer close(prStream)
+
+		query := `
+			SELECT
+				a.url,
+				a.status_code,
+				a.title
+			FROM pagereports AS a
+			WHERE a.crawl_id = ? AND a.crawled = 1 AND NOT EXISTS (
+				SELECT 1 FROM pagereports AS b
+				WHERE b.crawl_id = ? AND b.url_hash = a.url_hash AND b.crawled = 1
+			)`
+
+		rows, err := ds.db.Query(query, cid, prevCid)
+		if err != nil {
+			log.Printf("FindNewPageReports: %v\n", err)
+			return
+		}
+
+		for rows.Next() {
+			p := &models.PageReport{}
+			if err := rows.Scan(&p.URL, &p.StatusCode, &p.Title); err != nil {
+				log.Println(err)
+				continue
+			}
+
+			prStream <- p
+		}
+	}()
+
+	return prStream
+}
+
+// FindPageReportChanges sends through a read-only channel the page reports with a different
+// value in the field column in the crawls with ids cid and prevCid.
+func (ds *Datastore) FindPageReportChanges(cid, prevCid int64, field string) <-chan *diff.PageChange {
+	cStream := make(chan *diff.PageChange)
+
+	go func() {
+		defer close(cStream)
+
+		a, b, ok := diffColumn(field)
+		if !ok {
+			log.Printf("FindPageReportChanges: field %s not supported\n", field)
+			return
+		}
+
+		query := fmt.Sprintf(`
+			SELECT
+				a.url,
+				IFNULL(%[2]s, ''),
+				IFNULL(%[1]s, '')
+			FROM pagereports AS a
+			INNER JOIN pagereports AS b ON b.url_hash = a.url_hash AND b.crawl_id = ? AND b.crawled = 1
+			WHERE a.crawl_id = ? AND a.crawled = 1 AND NOT (%[1]s <=> %[2]s)`, a, b)
+
+		rows, err := ds.db.Query(query, prevCid, cid)
+		if err != nil {
+			log.Printf("FindPageReportChanges: %v\n", err)
+			return
+		}
+
+		for rows.Next() {
+			c := &diff.PageChange{}
+			if err := rows.Scan(&c.URL, &c.Previous, &c.Current); err != nil {
+				log.Println(err)
+				continue
+			}
+
+			cStream <- c
+		}
+	}()
+
+	return cStream
+}
+
+// FindNewIssues sends through a read-only channel the page reports and issue types that
+// are found in the crawl with id cid but not in the crawl with id prevCid.
+func (ds *Datastore) FindNewIssues(cid, prevCid int64) <-chan *diff.IssueChange {
+	iStream := make(chan *diff.IssueChange)
+
+	go func() {
+		defer close(iStream)
+
+		query := `
+			SELECT DISTINCT
+				pa.url,
+				issue_types.type
+			FROM issues AS a
+			INNER JOIN issue_types ON issue_types.id = a.issue_type_id
+			INNER JOIN pagereports AS pa ON pa.id = a.pagereport_id
+			WHERE a.crawl_id = ? AND NOT EXISTS (
+				SELECT 1 FROM issues AS b
+				INNER JOIN pagereports AS pb ON pb.id = b.pagereport_id
+				WHERE b.crawl_id = ? AND b.issue_type_id = a.issue_type_id AND pb.url_hash = pa.url_hash
+			)
+			ORDER BY issue_types.type`
+
+		rows, err := ds.db.Query(query, cid, prevCid)
+		if err != nil {
+			log.Printf("FindNewIssues: %v\n", err)
+			return
+		}
+
+		for rows.Next() {
+			c := &diff.IssueChange{}
+			if err := rows.Scan(&c.URL, &c.ErrorType); err != nil {
+				log.Println(err)
+				continue
+			}
+
+			iStream <- c
+		}
+	}()
+
+	return iStream
+}
@@ -1,7 +1,9 @@
 package datastore
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/export"
@@ -326,3 +328,143 @@ func (ds *Datastore) ExportHreflangs(crawl *models.Crawl) <-chan *export.Hreflan
 
 	return vStream
 }
+
+// Send all redirect hops through a read-only channel, ordered by origin URL and hop
+func (ds *Datastore) ExportRedirectHops(crawl *models.Crawl) <-chan *export.RedirectHop {
+	vStream := make(chan *export.RedirectHop)
+
+	go func() {
+		defer close(vStream)
+
+		query := `
+			SELECT
+				pagereports.url,
+				redirect_hops.hop,
+				redirect_hops.url,
+				redirect_hops.status_code,
+				redirect_hops.location
+			FROM redirect_hops
+			LEFT JOIN pagereports ON pagereports.id  = redirect_hops.pagereport_id
+			WHERE redirect_hops.crawl_id = ?
+			ORDER BY redirect_hops.pagereport_id, redirect_hops.hop`
+
+		rows, err := ds.db.Query(query, crawl.Id)
+		if err != nil {
+			log.Println(err)
+		}
+
+		for rows.Next() {
+			v := &export.RedirectHop{}
+			err := rows.Scan(&v.Origin, &v.Hop, &v.URL, &v.StatusCode, &v.Location)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			vStream <- v
+		}
+	}()
+
+	return vStream
+}
+
+// Send all external links with a checked destination URL through a read-only channel,
+// ordered by origin URL
+func (ds *Datastore) ExportExternalLinkStatuses(crawl *models.Crawl) <-chan *export.ExternalLinkStatus {
+	vStream := make(chan *export.ExternalLinkStatus)
+
+	go func() {
+		defer close(vStream)
+
+		query := `
+			SELECT
+				pagereports.url,
+				external_links.url,
+				external_links.text,
+				external_link_statuses.status_code,
+				external_link_statuses.redirect_url,
+				external_link_statuses.fetch_error
+			FROM external_links
+			INNER JOIN external_link_statuses
+				ON external_link_statuses.crawl_id = external_links.crawl_id
+				AND external_link_statuses.url_hash = external_links.url_hash
+			LEFT JOIN pagereports ON pagereports.id = external_links.pagereport_id
+			WHERE external_links.crawl_id = ?
+			ORDER BY external_links.pagereport_id`
+
+		rows, err := ds.db.Query(query, crawl.Id)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for rows.Next() {
+			v := &export.ExternalLinkStatus{}
+			err := rows.Scan(&v.Origin, &v.Destination, &v.Text, &v.StatusCode, &v.RedirectURL, &v.FetchError)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			vStream <- v
+		}
+	}()
+
+	return vStream
+}
+
+// Send the status of all the crawled images, scripts, styles, audios and videos of each page
+// through a read-only channel, ordered by origin URL
+func (ds *Datastore) ExportResourceStatuses(crawl *models.Crawl) <-chan *export.ResourceStatus {
+	vStream := make(chan *export.ResourceStatus)
+
+	go func() {
+		defer close(vStream)
+
+		resources := []string{}
+		for _, table := range []string{"images", "scripts", "styles", "audios", "videos"} {
+			resources = append(resources, fmt.Sprintf(`
+				SELECT
+					pagereport_id,
+					"%[1]s" AS type,
+					url,
+					url_hash
+				FROM %[1]s
+				WHERE crawl_id = ?`, table))
+		}
+
+		query := `
+			SELECT
+				origin.url,
+				resources.type,
+				resources.url,
+				pagereports.status_code,
+				pagereports.fetch_error
+			FROM (` + strings.Join(resources, " UNION ALL ") + `) AS resources
+			INNER JOIN pagereports
+				ON pagereports.crawl_id = ? AND pagereports.url_hash = resources.url_hash
+			LEFT JOIN pagereports AS origin ON origin.id = resources.pagereport_id
+			WHERE pagereports.crawled = 1
+			ORDER BY resources.pagereport_id`
+
+		id := crawl.Id
+		rows, err := ds.db.Query(query, id, id, id, id, id, id)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for rows.Next() {
+			v := &export.ResourceStatus{}
+			err := rows.Scan(&v.Origin, &v.Type, &v.URL, &v.StatusCode, &v.FetchError)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			vStream <- v
+		}
+	}()
+
+	return vStream
+}
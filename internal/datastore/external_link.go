@@ -0,0 +1,64 @@
+package datastore
+
+import (
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// GetExternalLinkURLs returns up to limit unique URLs of the external links found in the crawl.
+func (ds *Datastore) GetExternalLinkURLs(c *models.Crawl, limit int) []string {
+	query := `
+		SELECT
+			MIN(url)
+		FROM external_links
+		WHERE crawl_id = ?
+		GROUP BY url_hash
+		LIMIT ?`
+
+	urls := []string{}
+
+	rows, err := ds.db.Query(query, c.Id, limit)
+	if err != nil {
+		log.Println(err)
+		return urls
+	}
+
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			log.Println(err)
+			continue
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls
+}
+
+// SaveExternalLinkStatus stores the status of an external URL found in the crawl.
+func (ds *Datastore) SaveExternalLinkStatus(c *models.Crawl, s *models.ExternalLinkStatus) error {
+	query := `
+		INSERT IGNORE INTO external_link_statuses (
+			crawl_id,
+			url,
+			url_hash,
+			status_code,
+			redirect_url,
+			fetch_error
+		)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := ds.db.Exec(
+		query,
+		c.Id,
+		s.URL,
+		Hash(s.URL),
+		s.StatusCode,
+		Truncate(s.RedirectURL, 2048),
+		Truncate(s.FetchError, 64),
+	)
+
+	return err
+}
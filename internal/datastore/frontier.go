@@ -0,0 +1,206 @@
+package datastore
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Max number of rows inserted in each frontier insert query.
+const frontierBatchSize = 1000
+
+// Condition for unfinished crawls that can be resumed from a frontier checkpoint.
+// Crawls of projects with basic authentication, a login URL or proxy authentication can't be
+// resumed because the credentials are not stored.
+const resumableCrawl = `crawls.end IS NULL
+	AND crawls.checkpoint_pagereport_id IS NOT NULL
+	AND projects.basic_auth = 0
+	AND projects.login_url = ''
+	AND projects.proxy_auth = 0
+	AND projects.deleting = 0`
+
+// SaveCrawlFrontier stores a new checkpoint of the crawl's frontier. The seen URLs are added
+// to the ones already stored, while the queued URLs replace the ones of the previous checkpoint.
+func (ds *Datastore) SaveCrawlFrontier(c *models.Crawl, f *models.Frontier) error {
+	tx, err := ds.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM crawl_frontier WHERE crawl_id = ? AND queued = 1", c.Id)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]any{}
+	for _, u := range f.Seen {
+		rows = append(rows, []any{c.Id, u, 0, false})
+	}
+
+	for _, u := range f.Queued {
+		rows = append(rows, []any{c.Id, u.URL, u.Depth, true})
+	}
+
+	for i := 0; i < len(rows); i += frontierBatchSize {
+		batch := rows[i:min(i+frontierBatchSize, len(rows))]
+
+		placeholders := make([]string, len(batch))
+		v := []any{}
+		for n, r := range batch {
+			placeholders[n] = "(?, ?, ?, ?)"
+			v = append(v, r...)
+		}
+
+		query := "INSERT INTO crawl_frontier (crawl_id, url, depth, queued) VALUES " + strings.Join(placeholders, ",")
+		_, err = tx.Exec(query, v...)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE crawls
+		SET checkpoint_pagereport_id = ?, checkpoint_crawled = ?, excluded_by_rules = ?
+		WHERE id = ?`
+
+	_, err = tx.Exec(query, f.PageReportId, f.Crawled, c.ExcludedByRules, c.Id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetCrawlFrontier returns the crawl's last frontier checkpoint.
+func (ds *Datastore) GetCrawlFrontier(c *models.Crawl) (*models.Frontier, error) {
+	f := &models.Frontier{}
+
+	query := `SELECT checkpoint_pagereport_id, checkpoint_crawled FROM crawls WHERE id = ?`
+
+	var pageReportId sql.NullInt64
+	err := ds.db.QueryRow(query, c.Id).Scan(&pageReportId, &f.Crawled)
+	if err != nil {
+		return f, err
+	}
+
+	f.PageReportId = pageReportId.Int64
+
+	query = `SELECT url, depth, queued FROM crawl_frontier WHERE crawl_id = ?`
+
+	rows, err := ds.db.Query(query, c.Id)
+	if err != nil {
+		return f, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u models.FrontierURL
+		var queued bool
+		if err := rows.Scan(&u.URL, &u.Depth, &queued); err != nil {
+			log.Printf("GetCrawlFrontier: %v\n", err)
+			continue
+		}
+
+		f.Seen = append(f.Seen, u.URL)
+		if queued {
+			f.Queued = append(f.Queued, u)
+		}
+	}
+
+	return f, rows.Err()
+}
+
+// DeleteCrawlFrontier deletes the crawl's frontier once it's no longer needed.
+func (ds *Datastore) DeleteCrawlFrontier(c *models.Crawl) {
+	_, err := ds.db.Exec("DELETE FROM crawl_frontier WHERE crawl_id = ?", c.Id)
+	if err != nil {
+		log.Printf("DeleteCrawlFrontier: %v\n", err)
+	}
+}
+
+// GetResumableCrawls returns the unfinished crawls that can be resumed from a frontier checkpoint.
+func (ds *Datastore) GetResumableCrawls() []models.Crawl {
+	crawls := []models.Crawl{}
+	query := `
+		SELECT
+			crawls.id,
+			crawls.project_id,
+			crawls.start,
+			crawls.list_mode,
+			crawls.excluded_by_rules
+		FROM crawls
+		INNER JOIN projects ON projects.id = crawls.project_id
+		WHERE ` + resumableCrawl
+
+	rows, err := ds.db.Query(query)
+	if err != nil {
+		log.Printf("GetResumableCrawls: %v\n", err)
+		return crawls
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := models.Crawl{}
+		if err := rows.Scan(&c.Id, &c.ProjectId, &c.Start, &c.ListMode, &c.ExcludedByRules); err != nil {
+			log.Printf("GetResumableCrawls: %v\n", err)
+			continue
+		}
+
+		crawls = append(crawls, c)
+	}
+
+	return crawls
+}
+
+// FindCrawlProject returns the project the crawl belongs to.
+func (ds *Datastore) FindCrawlProject(c *models.Crawl) (models.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE id = ?`
+
+	return scanProject(ds.db.QueryRow(query, c.ProjectId))
+}
+
+// DeletePageReportsAfter deletes the crawl's page reports with an id greater than pageReportId.
+// The related data of the page reports is deleted on cascade.
+func (ds *Datastore) DeletePageReportsAfter(c *models.Crawl, pageReportId int64) error {
+	_, err := ds.db.Exec("DELETE FROM pagereports WHERE crawl_id = ? AND id > ?", c.Id, pageReportId)
+
+	return err
+}
+
+// CountCrawlTotals recomputes the crawl's URL and link counters from its stored page reports.
+func (ds *Datastore) CountCrawlTotals(c *models.Crawl) error {
+	query := `
+		SELECT
+			(SELECT count(id) FROM pagereports WHERE crawl_id = ? AND robotstxt_blocked = 0 AND noindex = 0),
+			(SELECT count(id) FROM pagereports WHERE crawl_id = ? AND robotstxt_blocked = 1),
+			(SELECT count(id) FROM pagereports WHERE crawl_id = ? AND robotstxt_blocked = 0 AND noindex = 1),
+			(SELECT count(id) FROM links WHERE crawl_id = ? AND nofollow = 0),
+			(SELECT count(id) FROM links WHERE crawl_id = ? AND nofollow = 1),
+			(SELECT count(id) FROM external_links WHERE crawl_id = ? AND nofollow = 0),
+			(SELECT count(id) FROM external_links WHERE crawl_id = ? AND nofollow = 1),
+			(SELECT count(id) FROM external_links WHERE crawl_id = ? AND sponsored = 1),
+			(SELECT count(id) FROM external_links WHERE crawl_id = ? AND ugc = 1)`
+
+	args := []any{}
+	for i := 0; i < 9; i++ {
+		args = append(args, c.Id)
+	}
+
+	return ds.db.QueryRow(query, args...).Scan(
+		&c.TotalURLs,
+		&c.BlockedByRobotstxt,
+		&c.Noindex,
+		&c.InternalFollowLinks,
+		&c.InternalNoFollowLinks,
+		&c.ExternalFollowLinks,
+		&c.ExternalNoFollowLinks,
+		&c.SponsoredLinks,
+		&c.UGCLinks,
+	)
+}
@@ -0,0 +1,109 @@
+package datastore
+
+import (
+	"log"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SaveMobileCrawl creates a new mobile crawl of the parent crawl, in list mode if the parent is.
+// Mobile crawls are not listed as the project's crawls, they are only compared with their parent crawl.
+func (ds *Datastore) SaveMobileCrawl(p models.Project, parent *models.Crawl) (*models.Crawl, error) {
+	query := "INSERT INTO crawls (project_id, list_mode, parent_id) VALUES (?, ?, ?)"
+	res, err := ds.db.Exec(query, p.Id, parent.ListMode, parent.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	cid, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Crawl{
+		Id:        cid,
+		ProjectId: p.Id,
+		URL:       p.URL,
+		Start:     time.Now(),
+		ListMode:  parent.ListMode,
+		ParentId:  parent.Id,
+	}, nil
+}
+
+// GetMobileCrawl returns the last finished mobile crawl of the crawl.
+func (ds *Datastore) GetMobileCrawl(c *models.Crawl) (models.Crawl, error) {
+	query := `
+		SELECT
+			id,
+			project_id,
+			start,
+			end,
+			total_urls,
+			blocked_by_robotstxt,
+			noindex,
+			list_mode
+		FROM crawls
+		WHERE parent_id = ? AND end IS NOT NULL
+		ORDER BY start DESC LIMIT 1`
+
+	crawl := models.Crawl{ParentId: c.Id}
+	err := ds.db.QueryRow(query, c.Id).Scan(
+		&crawl.Id,
+		&crawl.ProjectId,
+		&crawl.Start,
+		&crawl.End,
+		&crawl.TotalURLs,
+		&crawl.BlockedByRobotstxt,
+		&crawl.Noindex,
+		&crawl.ListMode,
+	)
+
+	return crawl, err
+}
+
+// GetCrawledURLs returns the URLs crawled in the crawl.
+func (ds *Datastore) GetCrawledURLs(c *models.Crawl) []string {
+	urls := []string{}
+	rows, err := ds.db.Query("SELECT url FROM pagereports WHERE crawl_id = ? AND crawled = 1 ORDER BY id", c.Id)
+	if err != nil {
+		log.Printf("GetCrawledURLs: %v\n", err)
+		return urls
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			log.Printf("GetCrawledURLs: %v\n", err)
+			continue
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls
+}
+
+// Returns the ids of the mobile crawls of the crawl.
+func (ds *Datastore) mobileCrawlIds(c *models.Crawl) []int64 {
+	ids := []int64{}
+	rows, err := ds.db.Query("SELECT id FROM crawls WHERE parent_id = ?", c.Id)
+	if err != nil {
+		log.Printf("mobileCrawlIds: %v\n", err)
+		return ids
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("mobileCrawlIds: %v\n", err)
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
@@ -1,12 +1,14 @@
 package datastore
 
 import (
+	"database/sql"
 	"log"
 	"math"
 	"sort"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/report"
+	"github.com/stjudewashere/seonaut/internal/simhash"
 )
 
 func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.PageReport, error) {
@@ -33,6 +35,10 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 			description,
 			robots,
 			noindex,
+			nofollow,
+			nosnippet,
+			max_snippet,
+			unavailable_after,
 			canonical,
 			h1,
 			h2,
@@ -43,9 +49,20 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 			crawled,
 			in_sitemap,
 			valid_lang,
-			depth
+			depth,
+			fetch_error,
+			dns_time,
+			connect_time,
+			tls_time,
+			ttfb,
+			download_time,
+			protocol,
+			content_encoding,
+			transfer_size,
+			content_hash,
+			simhash
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	stmt, err := ds.db.Prepare(query)
 	if err != nil {
@@ -67,8 +84,12 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 		r.Lang,
 		Truncate(r.Title, 2048),
 		Truncate(r.Description, 2048),
-		r.Robots,
+		Truncate(r.Robots, 1024),
 		r.Noindex,
+		r.Nofollow,
+		r.Nosnippet,
+		r.MaxSnippet,
+		r.UnavailableAfter,
 		r.Canonical,
 		Truncate(r.H1, 1024),
 		Truncate(r.H2, 1024),
@@ -80,6 +101,17 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 		r.InSitemap,
 		r.ValidLang,
 		r.Depth,
+		r.FetchError,
+		r.Timing.DNS,
+		r.Timing.Connect,
+		r.Timing.TLS,
+		r.Timing.TTFB,
+		r.Timing.Download,
+		Truncate(r.Transfer.Protocol, 16),
+		Truncate(r.Transfer.ContentEncoding, 32),
+		r.Transfer.Size,
+		r.ContentHash,
+		r.SimHash,
 	)
 	if err != nil {
 		return r, err
@@ -112,11 +144,11 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 	}
 
 	if len(r.ExternalLinks) > 0 {
-		sqlString := "INSERT INTO external_links (pagereport_id, crawl_id, url, rel, nofollow, text, sponsored, ugc) values "
+		sqlString := "INSERT INTO external_links (pagereport_id, crawl_id, url, rel, nofollow, text, sponsored, ugc, url_hash) values "
 		v := []interface{}{}
 		for _, l := range r.ExternalLinks {
-			sqlString += "(?, ?, ?, ?, ?, ?, ?, ?),"
-			v = append(v, lid, cid, l.URL, l.Rel, l.NoFollow, Truncate(l.Text, 1024), l.Sponsored, l.UGC)
+			sqlString += "(?, ?, ?, ?, ?, ?, ?, ?, ?),"
+			v = append(v, lid, cid, l.URL, l.Rel, l.NoFollow, Truncate(l.Text, 1024), l.Sponsored, l.UGC, Hash(l.URL))
 		}
 		sqlString = sqlString[0 : len(sqlString)-1]
 		stmt, err := ds.db.Prepare(sqlString)
@@ -148,12 +180,65 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 		}
 	}
 
+	if len(r.RedirectHops) > 0 {
+		sqlString := "INSERT INTO redirect_hops (pagereport_id, crawl_id, hop, url, status_code, location) values "
+		v := []interface{}{}
+		for _, h := range r.RedirectHops {
+			sqlString += "(?, ?, ?, ?, ?, ?),"
+			v = append(v, lid, cid, h.Hop, Truncate(h.URL, 2048), h.StatusCode, Truncate(h.Location, 2048))
+		}
+		sqlString = sqlString[0 : len(sqlString)-1]
+		stmt, err := ds.db.Prepare(sqlString)
+		if err != nil {
+			return r, err
+		}
+		defer stmt.Close()
+
+		_, err = stmt.Exec(v...)
+		if err != nil {
+			log.Printf("savePageReport\nCID: %v\n RedirectHops: %+v\nError: %+v\n", cid, v, err)
+		}
+	}
+
+	if r.RenderDiff != nil {
+		query := `
+			INSERT INTO render_diffs (
+				pagereport_id,
+				crawl_id,
+				raw_title,
+				rendered_title,
+				raw_links,
+				rendered_links,
+				added_links,
+				raw_words,
+				rendered_words
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+		d := r.RenderDiff
+		_, err := ds.db.Exec(
+			query,
+			lid,
+			cid,
+			Truncate(d.RawTitle, 2048),
+			Truncate(d.RenderedTitle, 2048),
+			d.RawLinks,
+			d.RenderedLinks,
+			d.AddedLinks,
+			d.RawWords,
+			d.RenderedWords,
+		)
+		if err != nil {
+			log.Printf("savePageReport\nCID: %v\n RenderDiff: %+v\nError: %+v\n", cid, d, err)
+		}
+	}
+
 	if len(r.Images) > 0 {
-		sqlString := "INSERT INTO images (pagereport_id, url, alt, crawl_id) values "
+		sqlString := "INSERT INTO images (pagereport_id, url, alt, crawl_id, url_hash) values "
 		v := []interface{}{}
 		for _, i := range r.Images {
-			sqlString += "(?, ?, ?, ?),"
-			v = append(v, lid, i.URL, Truncate(i.Alt, 1024), cid)
+			sqlString += "(?, ?, ?, ?, ?),"
+			v = append(v, lid, i.URL, Truncate(i.Alt, 1024), cid, Hash(i.URL))
 		}
 		sqlString = sqlString[0 : len(sqlString)-1]
 		stmt, _ = ds.db.Prepare(sqlString)
@@ -184,12 +269,12 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 	}
 
 	if len(r.Audios) > 0 {
-		sqlString := "INSERT INTO audios (pagereport_id, url, crawl_id) values "
+		sqlString := "INSERT INTO audios (pagereport_id, url, crawl_id, url_hash) values "
 
 		v := []interface{}{}
 		for _, i := range r.Audios {
-			sqlString += "(?, ?, ?),"
-			v = append(v, lid, i, cid)
+			sqlString += "(?, ?, ?, ?),"
+			v = append(v, lid, i, cid, Hash(i))
 		}
 		sqlString = sqlString[0 : len(sqlString)-1]
 		stmt, _ = ds.db.Prepare(sqlString)
@@ -202,12 +287,12 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 	}
 
 	if len(r.Videos) > 0 {
-		sqlString := "INSERT INTO videos (pagereport_id, url, crawl_id) values "
+		sqlString := "INSERT INTO videos (pagereport_id, url, crawl_id, url_hash) values "
 
 		v := []interface{}{}
 		for _, i := range r.Videos {
-			sqlString += "(?, ?, ?),"
-			v = append(v, lid, i, cid)
+			sqlString += "(?, ?, ?, ?),"
+			v = append(v, lid, i, cid, Hash(i))
 		}
 		sqlString = sqlString[0 : len(sqlString)-1]
 		stmt, _ = ds.db.Prepare(sqlString)
@@ -220,11 +305,11 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 	}
 
 	if len(r.Scripts) > 0 {
-		sqlString := "INSERT INTO scripts (pagereport_id, url, crawl_id) values "
+		sqlString := "INSERT INTO scripts (pagereport_id, url, crawl_id, url_hash) values "
 		v := []interface{}{}
 		for _, s := range r.Scripts {
-			sqlString += "(?, ?, ?),"
-			v = append(v, lid, s, cid)
+			sqlString += "(?, ?, ?, ?),"
+			v = append(v, lid, s, cid, Hash(s))
 		}
 		sqlString = sqlString[0 : len(sqlString)-1]
 		stmt, _ := ds.db.Prepare(sqlString)
@@ -237,12 +322,12 @@ func (ds *Datastore) SavePageReport(r *models.PageReport, cid int64) (*models.Pa
 	}
 
 	if len(r.Styles) > 0 {
-		sqlString := "INSERT INTO styles (pagereport_id, url, crawl_id) values "
+		sqlString := "INSERT INTO styles (pagereport_id, url, crawl_id, url_hash) values "
 		v := []interface{}{}
 
 		for _, s := range r.Styles {
-			sqlString += "(?, ?, ?),"
-			v = append(v, lid, s, cid)
+			sqlString += "(?, ?, ?, ?),"
+			v = append(v, lid, s, cid, Hash(s))
 
 		}
 		sqlString = sqlString[0 : len(sqlString)-1]
@@ -280,6 +365,10 @@ func (ds *Datastore) FindAllPageReportsByCrawlId(cid int64) <-chan *models.PageR
 				description,
 				robots,
 				noindex,
+				nofollow,
+				nosnippet,
+				max_snippet,
+				unavailable_after,
 				canonical,
 				h1,
 				h2,
@@ -290,7 +379,18 @@ func (ds *Datastore) FindAllPageReportsByCrawlId(cid int64) <-chan *models.PageR
 				crawled,
 				in_sitemap,
 				valid_lang,
-				depth
+				depth,
+				fetch_error,
+				dns_time,
+				connect_time,
+				tls_time,
+				ttfb,
+				download_time,
+				protocol,
+				content_encoding,
+				transfer_size,
+				content_hash,
+				simhash
 			FROM pagereports
 			WHERE crawl_id = ?`
 
@@ -313,6 +413,10 @@ func (ds *Datastore) FindAllPageReportsByCrawlId(cid int64) <-chan *models.PageR
 				&p.Description,
 				&p.Robots,
 				&p.Noindex,
+				&p.Nofollow,
+				&p.Nosnippet,
+				&p.MaxSnippet,
+				&p.UnavailableAfter,
 				&p.Canonical,
 				&p.H1,
 				&p.H2,
@@ -324,6 +428,17 @@ func (ds *Datastore) FindAllPageReportsByCrawlId(cid int64) <-chan *models.PageR
 				&p.InSitemap,
 				&p.ValidLang,
 				&p.Depth,
+				&p.FetchError,
+				&p.Timing.DNS,
+				&p.Timing.Connect,
+				&p.Timing.TLS,
+				&p.Timing.TTFB,
+				&p.Timing.Download,
+				&p.Transfer.Protocol,
+				&p.Transfer.ContentEncoding,
+				&p.Transfer.Size,
+				&p.ContentHash,
+				&p.SimHash,
 			)
 			if err != nil {
 				log.Println(err)
@@ -357,6 +472,10 @@ func (ds *Datastore) FindAllPageReportsByCrawlIdAndErrorType(cid int64, et strin
 				description,
 				robots,
 				noindex,
+				nofollow,
+				nosnippet,
+				max_snippet,
+				unavailable_after,
 				canonical,
 				h1,
 				h2,
@@ -367,7 +486,18 @@ func (ds *Datastore) FindAllPageReportsByCrawlIdAndErrorType(cid int64, et strin
 				crawled,
 				in_sitemap,
 				valid_lang,
-				depth
+				depth,
+				fetch_error,
+				dns_time,
+				connect_time,
+				tls_time,
+				ttfb,
+				download_time,
+				protocol,
+				content_encoding,
+				transfer_size,
+				content_hash,
+				simhash
 			FROM pagereports
 			WHERE crawl_id = ?
 			AND id IN (
@@ -397,6 +527,10 @@ func (ds *Datastore) FindAllPageReportsByCrawlIdAndErrorType(cid int64, et strin
 				&p.Description,
 				&p.Robots,
 				&p.Noindex,
+				&p.Nofollow,
+				&p.Nosnippet,
+				&p.MaxSnippet,
+				&p.UnavailableAfter,
 				&p.Canonical,
 				&p.H1,
 				&p.H2,
@@ -408,6 +542,17 @@ func (ds *Datastore) FindAllPageReportsByCrawlIdAndErrorType(cid int64, et strin
 				&p.InSitemap,
 				&p.ValidLang,
 				&p.Depth,
+				&p.FetchError,
+				&p.Timing.DNS,
+				&p.Timing.Connect,
+				&p.Timing.TLS,
+				&p.Timing.TTFB,
+				&p.Timing.Download,
+				&p.Transfer.Protocol,
+				&p.Transfer.ContentEncoding,
+				&p.Transfer.Size,
+				&p.ContentHash,
+				&p.SimHash,
 			)
 			if err != nil {
 				log.Println(err)
@@ -436,6 +581,10 @@ func (ds *Datastore) FindPageReportById(rid int) models.PageReport {
 			description,
 			robots,
 			noindex,
+			nofollow,
+			nosnippet,
+			max_snippet,
+			unavailable_after,
 			canonical,
 			h1,
 			h2,
@@ -446,7 +595,18 @@ func (ds *Datastore) FindPageReportById(rid int) models.PageReport {
 			crawled,
 			in_sitemap,
 			valid_lang,
-			depth
+			depth,
+			fetch_error,
+			dns_time,
+			connect_time,
+			tls_time,
+			ttfb,
+			download_time,
+			protocol,
+			content_encoding,
+			transfer_size,
+			content_hash,
+			simhash
 		FROM pagereports
 		WHERE id = ?`
 
@@ -465,6 +625,10 @@ func (ds *Datastore) FindPageReportById(rid int) models.PageReport {
 		&p.Description,
 		&p.Robots,
 		&p.Noindex,
+		&p.Nofollow,
+		&p.Nosnippet,
+		&p.MaxSnippet,
+		&p.UnavailableAfter,
 		&p.Canonical,
 		&p.H1,
 		&p.H2,
@@ -476,6 +640,17 @@ func (ds *Datastore) FindPageReportById(rid int) models.PageReport {
 		&p.InSitemap,
 		&p.ValidLang,
 		&p.Depth,
+		&p.FetchError,
+		&p.Timing.DNS,
+		&p.Timing.Connect,
+		&p.Timing.TLS,
+		&p.Timing.TTFB,
+		&p.Timing.Download,
+		&p.Transfer.Protocol,
+		&p.Transfer.ContentEncoding,
+		&p.Transfer.Size,
+		&p.ContentHash,
+		&p.SimHash,
 	)
 	if err != nil {
 		log.Println(err)
@@ -497,6 +672,68 @@ func (ds *Datastore) FindPageReportById(rid int) models.PageReport {
 		p.Hreflangs = append(p.Hreflangs, h)
 	}
 
+	rhrows, err := ds.db.Query("SELECT hop, url, status_code, location FROM redirect_hops WHERE pagereport_id = ? ORDER BY hop", rid)
+	if err != nil {
+		log.Println(err)
+	}
+
+	for rhrows.Next() {
+		h := models.RedirectHop{}
+		err = rhrows.Scan(&h.Hop, &h.URL, &h.StatusCode, &h.Location)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		p.RedirectHops = append(p.RedirectHops, h)
+	}
+
+	uvrows, err := ds.db.Query(`
+		SELECT url_variants.variant
+		FROM url_variants
+		INNER JOIN pagereports ON pagereports.crawl_id = url_variants.crawl_id
+		WHERE pagereports.id = ? AND url_variants.url_hash = ?
+		ORDER BY url_variants.id`, rid, Hash(p.URL))
+	if err != nil {
+		log.Println(err)
+	}
+
+	for uvrows.Next() {
+		var v string
+		if err := uvrows.Scan(&v); err != nil {
+			log.Println(err)
+			continue
+		}
+
+		p.Variants = append(p.Variants, v)
+	}
+
+	d := &models.RenderDiff{}
+	err = ds.db.QueryRow(`
+		SELECT
+			raw_title,
+			rendered_title,
+			raw_links,
+			rendered_links,
+			added_links,
+			raw_words,
+			rendered_words
+		FROM render_diffs
+		WHERE pagereport_id = ?`, rid).Scan(
+		&d.RawTitle,
+		&d.RenderedTitle,
+		&d.RawLinks,
+		&d.RenderedLinks,
+		&d.AddedLinks,
+		&d.RawWords,
+		&d.RenderedWords,
+	)
+	if err == nil {
+		p.RenderDiff = d
+	} else if err != sql.ErrNoRows {
+		log.Println(err)
+	}
+
 	irows, err := ds.db.Query("SELECT url, alt FROM images WHERE pagereport_id = ?", rid)
 	if err != nil {
 		log.Println(err)
@@ -1162,3 +1399,163 @@ func (ds *Datastore) GetStatusCodeByDepth(cid int64) []report.StatusCodeByDepth
 
 	return s
 }
+
+// GetResponseTimeRanges returns the number of crawled pages in each range of total download time.
+// Pages without a download time, such as the ones crawled before it was recorded, are not counted.
+func (ds *Datastore) GetResponseTimeRanges(cid int64) []report.ResponseTimeRange {
+	query := `
+	SELECT
+		r.name,
+		COUNT(pr.id)
+	FROM
+		(SELECT 0 AS low, 200 AS high, '0-200ms' AS name
+		UNION SELECT 200, 500, '200-500ms'
+		UNION SELECT 500, 1000, '500ms-1s'
+		UNION SELECT 1000, 2000, '1-2s'
+		UNION SELECT 2000, 5000, '2-5s'
+		UNION SELECT 5000, 2147483647, '5s+') r
+	LEFT JOIN pagereports pr ON pr.crawl_id = ? AND pr.crawled = 1 AND pr.download_time > 0
+		AND pr.download_time >= r.low AND pr.download_time < r.high
+	GROUP BY r.low, r.name
+	ORDER BY r.low;
+	`
+
+	s := []report.ResponseTimeRange{}
+
+	rows, err := ds.db.Query(query, cid)
+	if err != nil {
+		log.Println(err)
+		return s
+	}
+
+	for rows.Next() {
+		c := report.ResponseTimeRange{}
+		err := rows.Scan(&c.Range, &c.Pages)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		s = append(s, c)
+	}
+
+	return s
+}
+
+// FindSlowestPageReports returns up to limit crawled page reports with the longest total download time.
+func (ds *Datastore) FindSlowestPageReports(cid int64, limit int) []models.PageReport {
+	query := `
+		SELECT
+			id,
+			url,
+			status_code,
+			ttfb,
+			download_time
+		FROM pagereports
+		WHERE crawl_id = ? AND crawled = 1 AND download_time > 0
+		ORDER BY download_time DESC
+		LIMIT ?`
+
+	pageReports := []models.PageReport{}
+
+	rows, err := ds.db.Query(query, cid, limit)
+	if err != nil {
+		log.Println(err)
+		return pageReports
+	}
+
+	for rows.Next() {
+		p := models.PageReport{}
+		err := rows.Scan(&p.Id, &p.URL, &p.StatusCode, &p.Timing.TTFB, &p.Timing.Download)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		pageReports = append(pageReports, p)
+	}
+
+	return pageReports
+}
+
+// CountBytesByMediaType returns the sum of the uncompressed and transferred bytes of
+// the crawled page reports of each media type, sorted by the uncompressed bytes.
+func (ds *Datastore) CountBytesByMediaType(cid int64) []report.MediaTypeBytes {
+	query := `
+		SELECT
+			media_type,
+			SUM(size),
+			SUM(transfer_size)
+		FROM pagereports
+		WHERE crawl_id = ? AND crawled = 1 AND media_type != ''
+		GROUP BY media_type
+		ORDER BY SUM(size) DESC
+		LIMIT 10`
+
+	m := []report.MediaTypeBytes{}
+
+	rows, err := ds.db.Query(query, cid)
+	if err != nil {
+		log.Println(err)
+		return m
+	}
+
+	for rows.Next() {
+		c := report.MediaTypeBytes{}
+		err := rows.Scan(&c.MediaType, &c.Size, &c.TransferSize)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		m = append(m, c)
+	}
+
+	return m
+}
+
+// FindDuplicatePageReports returns the crawled HTML pages with the same visible text as the
+// pageReport, or with a SimHash fingerprint within the near-duplicate distance, exact duplicates first.
+func (ds *Datastore) FindDuplicatePageReports(pageReport *models.PageReport, cid int64, limit int) []report.DuplicatePage {
+	query := `
+		SELECT
+			id,
+			url,
+			content_hash = ?
+		FROM pagereports
+		WHERE crawl_id = ? AND id != ? AND media_type = "text/html" AND status_code >= 200
+		AND status_code < 300 AND (canonical = "" OR canonical = url) AND crawled = 1
+		AND content_hash != "" AND (content_hash = ? OR BIT_COUNT(simhash ^ ?) <= ?)
+		ORDER BY content_hash = ? DESC, BIT_COUNT(simhash ^ ?), url
+		LIMIT ?`
+
+	duplicates := []report.DuplicatePage{}
+
+	rows, err := ds.db.Query(
+		query,
+		pageReport.ContentHash,
+		cid,
+		pageReport.Id,
+		pageReport.ContentHash,
+		pageReport.SimHash,
+		simhash.NearDuplicateDistance,
+		pageReport.ContentHash,
+		pageReport.SimHash,
+		limit,
+	)
+	if err != nil {
+		log.Println(err)
+		return duplicates
+	}
+
+	for rows.Next() {
+		d := report.DuplicatePage{}
+		err := rows.Scan(&d.Id, &d.URL, &d.Exact)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		duplicates = append(duplicates, d)
+	}
+
+	return duplicates
+}
@@ -20,9 +20,30 @@ func (ds *Datastore) SaveProject(project *models.Project, uid int) {
 			crawl_sitemap,
 			allow_subdomains,
 			basic_auth,
+			keep_crawls,
+			max_pagereports,
+			max_depth,
+			crawl_threads,
+			crawl_delay,
+			crawl_timeout,
+			render_js,
+			crawl_headers,
+			use_cookies,
+			login_url,
+			include_rules,
+			exclude_rules,
+			normalize_urls,
+			strip_params,
+			ignore_params,
+			proxy_url,
+			proxy_auth,
+			mobile_crawl,
+			check_external_links,
+			robots_draft,
+			robots_token,
 			user_id
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	stmt, _ := ds.db.Prepare(query)
@@ -35,6 +56,27 @@ func (ds *Datastore) SaveProject(project *models.Project, uid int) {
 		project.CrawlSitemap,
 		project.AllowSubdomains,
 		project.BasicAuth,
+		project.KeepCrawls,
+		project.MaxPageReports,
+		project.MaxDepth,
+		project.CrawlThreads,
+		project.CrawlDelay,
+		project.CrawlTimeout,
+		project.RenderJS,
+		project.CrawlHeaders,
+		project.UseCookies,
+		project.LoginURL,
+		project.IncludeRules,
+		project.ExcludeRules,
+		project.NormalizeURLs,
+		project.StripParams,
+		project.IgnoreParams,
+		project.ProxyURL,
+		project.ProxyAuth,
+		project.MobileCrawl,
+		project.CheckLinks,
+		project.RobotsDraft,
+		project.RobotsToken,
 		uid,
 	)
 	if err != nil {
@@ -42,20 +84,93 @@ func (ds *Datastore) SaveProject(project *models.Project, uid int) {
 	}
 }
 
+// Columns selected from the projects table to be scanned with scanProject.
+const projectColumns = `
+	id,
+	url,
+	ignore_robotstxt,
+	follow_nofollow,
+	include_noindex,
+	crawl_sitemap,
+	allow_subdomains,
+	basic_auth,
+	keep_crawls,
+	crawl_schedule,
+	next_crawl,
+	max_pagereports,
+	max_depth,
+	crawl_threads,
+	crawl_delay,
+	crawl_timeout,
+	render_js,
+	crawl_headers,
+	use_cookies,
+	login_url,
+	include_rules,
+	exclude_rules,
+	normalize_urls,
+	strip_params,
+	ignore_params,
+	proxy_url,
+	proxy_auth,
+	mobile_crawl,
+	check_external_links,
+	robots_draft,
+	robots_token,
+	deleting,
+	created`
+
+// scanner is implemented by both sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Scan a project selected with the projectColumns.
+func scanProject(s scanner) (models.Project, error) {
+	p := models.Project{}
+	err := s.Scan(
+		&p.Id,
+		&p.URL,
+		&p.IgnoreRobotsTxt,
+		&p.FollowNofollow,
+		&p.IncludeNoindex,
+		&p.CrawlSitemap,
+		&p.AllowSubdomains,
+		&p.BasicAuth,
+		&p.KeepCrawls,
+		&p.Schedule,
+		&p.NextCrawl,
+		&p.MaxPageReports,
+		&p.MaxDepth,
+		&p.CrawlThreads,
+		&p.CrawlDelay,
+		&p.CrawlTimeout,
+		&p.RenderJS,
+		&p.CrawlHeaders,
+		&p.UseCookies,
+		&p.LoginURL,
+		&p.IncludeRules,
+		&p.ExcludeRules,
+		&p.NormalizeURLs,
+		&p.StripParams,
+		&p.IgnoreParams,
+		&p.ProxyURL,
+		&p.ProxyAuth,
+		&p.MobileCrawl,
+		&p.CheckLinks,
+		&p.RobotsDraft,
+		&p.RobotsToken,
+		&p.Deleting,
+		&p.Created,
+	)
+
+	return p, err
+}
+
 func (ds *Datastore) FindProjectsByUser(uid int) []models.Project {
 	var projects []models.Project
 	query := `
-		SELECT
-			id,
-			url,
-			ignore_robotstxt,
-			follow_nofollow,
-			include_noindex,
-			crawl_sitemap,
-			allow_subdomains,
-			basic_auth,
-			deleting,
-			created
+		SELECT ` + projectColumns + `
 		FROM projects
 		WHERE user_id = ?
 		ORDER BY url ASC`
@@ -67,19 +182,7 @@ func (ds *Datastore) FindProjectsByUser(uid int) []models.Project {
 	}
 
 	for rows.Next() {
-		p := models.Project{}
-		err := rows.Scan(
-			&p.Id,
-			&p.URL,
-			&p.IgnoreRobotsTxt,
-			&p.FollowNofollow,
-			&p.IncludeNoindex,
-			&p.CrawlSitemap,
-			&p.AllowSubdomains,
-			&p.BasicAuth,
-			&p.Deleting,
-			&p.Created,
-		)
+		p, err := scanProject(rows)
 		if err != nil {
 			log.Println(err)
 			continue
@@ -93,35 +196,13 @@ func (ds *Datastore) FindProjectsByUser(uid int) []models.Project {
 
 func (ds *Datastore) FindProjectById(id int, uid int) (models.Project, error) {
 	query := `
-		SELECT
-			id,
-			url,
-			ignore_robotstxt,
-			follow_nofollow,
-			include_noindex,
-			crawl_sitemap,
-			allow_subdomains,
-			basic_auth,
-			deleting,
-			created
+		SELECT ` + projectColumns + `
 		FROM projects
 		WHERE id = ? AND user_id = ?`
 
 	row := ds.db.QueryRow(query, id, uid)
 
-	p := models.Project{}
-	err := row.Scan(
-		&p.Id,
-		&p.URL,
-		&p.IgnoreRobotsTxt,
-		&p.FollowNofollow,
-		&p.IncludeNoindex,
-		&p.CrawlSitemap,
-		&p.AllowSubdomains,
-		&p.BasicAuth,
-		&p.Deleting,
-		&p.Created,
-	)
+	p, err := scanProject(row)
 	if err != nil {
 		log.Println(err)
 		return p, err
@@ -130,10 +211,53 @@ func (ds *Datastore) FindProjectById(id int, uid int) (models.Project, error) {
 	return p, nil
 }
 
-func (ds *Datastore) SaveCrawl(p models.Project) (*models.Crawl, error) {
-	stmt, _ := ds.db.Prepare("INSERT INTO crawls (project_id) VALUES (?)")
+// FindScheduledProjects returns the projects with a crawl scheduled before time t.
+// Projects using HTTP basic authentication, a login URL or proxy authentication are not included
+// as their credentials are not stored.
+func (ds *Datastore) FindScheduledProjects(t time.Time) []models.Project {
+	var projects []models.Project
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE next_crawl IS NOT NULL AND next_crawl <= ? AND crawl_schedule != ''
+			AND deleting = 0 AND basic_auth = 0 AND login_url = '' AND proxy_auth = 0`
+
+	rows, err := ds.db.Query(query, t)
+	if err != nil {
+		log.Println(err)
+		return projects
+	}
+
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		projects = append(projects, p)
+	}
+
+	return projects
+}
+
+// UpdateNextCrawl stores the time of the project's next scheduled crawl.
+func (ds *Datastore) UpdateNextCrawl(p *models.Project) error {
+	query := `UPDATE projects SET next_crawl = ? WHERE id = ?`
+	_, err := ds.db.Exec(query, p.NextCrawl, p.Id)
+	if err != nil {
+		log.Printf("UpdateNextCrawl: pid %d %v\n", p.Id, err)
+	}
+
+	return err
+}
+
+// SaveCrawl creates a new crawl for the project. If listMode is true the crawl
+// only crawls a list of URLs.
+func (ds *Datastore) SaveCrawl(p models.Project, listMode bool) (*models.Crawl, error) {
+	stmt, _ := ds.db.Prepare("INSERT INTO crawls (project_id, list_mode) VALUES (?, ?)")
 	defer stmt.Close()
-	res, err := stmt.Exec(p.Id)
+	res, err := stmt.Exec(p.Id, listMode)
 
 	if err != nil {
 		return nil, err
@@ -149,6 +273,7 @@ func (ds *Datastore) SaveCrawl(p models.Project) (*models.Crawl, error) {
 		ProjectId: p.Id,
 		URL:       p.URL,
 		Start:     time.Now(),
+		ListMode:  listMode,
 	}, nil
 }
 
@@ -160,6 +285,7 @@ func (ds *Datastore) SaveEndCrawl(c *models.Crawl) (*models.Crawl, error) {
 			end = ?,
 			total_urls = ?,
 			blocked_by_robotstxt = ?,
+			excluded_by_rules = ?,
 			noindex = ?,
 			robotstxt_exists = ?,
 			sitemap_exists = ?,
@@ -183,6 +309,7 @@ func (ds *Datastore) SaveEndCrawl(c *models.Crawl) (*models.Crawl, error) {
 		c.End,
 		c.TotalURLs,
 		c.BlockedByRobotstxt,
+		c.ExcludedByRules,
 		c.Noindex,
 		c.RobotstxtExists,
 		c.SitemapExists,
@@ -221,9 +348,11 @@ func (ds *Datastore) GetLastCrawl(p *models.Project) models.Crawl {
 			links_external_follow,
 			links_external_nofollow,
 			links_sponsored,
-			links_ugc
+			links_ugc,
+			list_mode,
+			excluded_by_rules
 		FROM crawls
-		WHERE project_id = ?
+		WHERE project_id = ? AND parent_id IS NULL
 		ORDER BY start DESC LIMIT 1`
 
 	row := ds.db.QueryRow(query, p.Id)
@@ -247,6 +376,8 @@ func (ds *Datastore) GetLastCrawl(p *models.Project) models.Crawl {
 		&crawl.ExternalNoFollowLinks,
 		&crawl.SponsoredLinks,
 		&crawl.UGCLinks,
+		&crawl.ListMode,
+		&crawl.ExcludedByRules,
 	)
 	if err != nil && err != sql.ErrNoRows {
 		log.Printf("GetLastCrawl project id %d: %v\n", p.Id, err)
@@ -268,9 +399,10 @@ func (ds *Datastore) GetLastCrawls(p models.Project, limit int) []models.Crawl {
 			alert_issues,
 			warning_issues,
 			blocked_by_robotstxt,
+			excluded_by_rules,
 			noindex
 		FROM crawls
-		WHERE project_id = ?
+		WHERE project_id = ? AND parent_id IS NULL
 		ORDER BY start DESC LIMIT ?`
 
 	crawls := []models.Crawl{}
@@ -292,6 +424,7 @@ func (ds *Datastore) GetLastCrawls(p models.Project, limit int) []models.Crawl {
 			&crawl.AlertIssues,
 			&crawl.WarningIssues,
 			&crawl.BlockedByRobotstxt,
+			&crawl.ExcludedByRules,
 			&crawl.Noindex,
 		)
 		if err != nil {
@@ -351,7 +484,30 @@ func (ds *Datastore) UpdateProject(p *models.Project) error {
 			include_noindex = ?,
 			crawl_sitemap = ?,
 			allow_subdomains = ?,
-			basic_auth = ?
+			basic_auth = ?,
+			keep_crawls = ?,
+			crawl_schedule = ?,
+			next_crawl = ?,
+			max_pagereports = ?,
+			max_depth = ?,
+			crawl_threads = ?,
+			crawl_delay = ?,
+			crawl_timeout = ?,
+			render_js = ?,
+			crawl_headers = ?,
+			use_cookies = ?,
+			login_url = ?,
+			include_rules = ?,
+			exclude_rules = ?,
+			normalize_urls = ?,
+			strip_params = ?,
+			ignore_params = ?,
+			proxy_url = ?,
+			proxy_auth = ?,
+			mobile_crawl = ?,
+			check_external_links = ?,
+			robots_draft = ?,
+			robots_token = ?
 		WHERE id = ?
 	`
 	_, err := ds.db.Exec(
@@ -362,6 +518,29 @@ func (ds *Datastore) UpdateProject(p *models.Project) error {
 		p.CrawlSitemap,
 		p.AllowSubdomains,
 		p.BasicAuth,
+		p.KeepCrawls,
+		p.Schedule,
+		p.NextCrawl,
+		p.MaxPageReports,
+		p.MaxDepth,
+		p.CrawlThreads,
+		p.CrawlDelay,
+		p.CrawlTimeout,
+		p.RenderJS,
+		p.CrawlHeaders,
+		p.UseCookies,
+		p.LoginURL,
+		p.IncludeRules,
+		p.ExcludeRules,
+		p.NormalizeURLs,
+		p.StripParams,
+		p.IgnoreParams,
+		p.ProxyURL,
+		p.ProxyAuth,
+		p.MobileCrawl,
+		p.CheckLinks,
+		p.RobotsDraft,
+		p.RobotsToken,
 		p.Id,
 	)
 	if err != nil {
@@ -371,7 +550,9 @@ func (ds *Datastore) UpdateProject(p *models.Project) error {
 	return err
 }
 
-func (ds *Datastore) GetPreviousCrawl(p *models.Project) (*models.Crawl, error) {
+// GetExpiredCrawls returns the project's crawls that still have data stored but are
+// older than the number of crawls to be kept.
+func (ds *Datastore) GetExpiredCrawls(p *models.Project, keep int) []models.Crawl {
 	query := `
 		SELECT
 			id,
@@ -386,35 +567,80 @@ func (ds *Datastore) GetPreviousCrawl(p *models.Project) (*models.Crawl, error)
 			blocked_by_robotstxt,
 			noindex
 		FROM crawls
-		WHERE project_id = ?
-		ORDER BY end DESC
-		LIMIT 1, 1`
+		WHERE project_id = ? AND parent_id IS NULL AND id NOT IN (
+			SELECT id FROM (
+				SELECT id FROM crawls WHERE project_id = ? AND parent_id IS NULL ORDER BY start DESC LIMIT ?
+			) AS kept
+		) AND EXISTS (SELECT 1 FROM pagereports WHERE pagereports.crawl_id = crawls.id)
+		ORDER BY start DESC`
+
+	return ds.crawlsQuery(query, p.Id, p.Id, keep)
+}
 
-	row := ds.db.QueryRow(query, p.Id)
+// GetRetainedCrawls returns the project's finished crawls that still have
+// their data stored, ordered from the newest to the oldest.
+func (ds *Datastore) GetRetainedCrawls(p *models.Project) []models.Crawl {
+	query := `
+		SELECT
+			id,
+			start,
+			end,
+			total_urls,
+			total_issues,
+			issues_end,
+			critical_issues,
+			alert_issues,
+			warning_issues,
+			blocked_by_robotstxt,
+			noindex
+		FROM crawls
+		WHERE project_id = ? AND parent_id IS NULL AND issues_end IS NOT NULL
+			AND EXISTS (SELECT 1 FROM pagereports WHERE pagereports.crawl_id = crawls.id)
+		ORDER BY start DESC`
 
-	crawl := &models.Crawl{}
-	err := row.Scan(
-		&crawl.Id,
-		&crawl.Start,
-		&crawl.End,
-		&crawl.TotalURLs,
-		&crawl.TotalIssues,
-		&crawl.IssuesEnd,
-		&crawl.CriticalIssues,
-		&crawl.AlertIssues,
-		&crawl.WarningIssues,
-		&crawl.BlockedByRobotstxt,
-		&crawl.Noindex,
-	)
+	return ds.crawlsQuery(query, p.Id)
+}
 
+func (ds *Datastore) crawlsQuery(query string, args ...interface{}) []models.Crawl {
+	crawls := []models.Crawl{}
+	rows, err := ds.db.Query(query, args...)
 	if err != nil {
-		return crawl, err
+		log.Println(err)
+		return crawls
 	}
 
-	return crawl, nil
+	for rows.Next() {
+		crawl := models.Crawl{}
+		err := rows.Scan(
+			&crawl.Id,
+			&crawl.Start,
+			&crawl.End,
+			&crawl.TotalURLs,
+			&crawl.TotalIssues,
+			&crawl.IssuesEnd,
+			&crawl.CriticalIssues,
+			&crawl.AlertIssues,
+			&crawl.WarningIssues,
+			&crawl.BlockedByRobotstxt,
+			&crawl.Noindex,
+		)
+		if err != nil {
+			log.Printf("crawlsQuery: %v\n", err)
+			continue
+		}
+
+		crawls = append(crawls, crawl)
+	}
+
+	return crawls
 }
 
+// DeleteCrawlData deletes the crawl's data, including the data of its mobile crawls.
 func (ds *Datastore) DeleteCrawlData(crawl *models.Crawl) {
+	for _, id := range ds.mobileCrawlIds(crawl) {
+		ds.DeleteCrawlData(&models.Crawl{Id: id})
+	}
+
 	var deleteFunc func(cid int64, table string)
 	deleteFunc = func(cid int64, table string) {
 		query := fmt.Sprintf("DELETE FROM %s WHERE crawl_id = ? ORDER BY id DESC LIMIT 1000", table)
@@ -447,7 +673,15 @@ func (ds *Datastore) DeleteCrawlData(crawl *models.Crawl) {
 	deleteFunc(crawl.Id, "iframes")
 	deleteFunc(crawl.Id, "audios")
 	deleteFunc(crawl.Id, "videos")
+	deleteFunc(crawl.Id, "redirect_hops")
+	deleteFunc(crawl.Id, "render_diffs")
+	deleteFunc(crawl.Id, "url_variants")
+	deleteFunc(crawl.Id, "tls_certificates")
+	deleteFunc(crawl.Id, "external_link_statuses")
+	deleteFunc(crawl.Id, "sitemap_files")
+	deleteFunc(crawl.Id, "robotstxt_files")
 	deleteFunc(crawl.Id, "pagereports")
+	deleteFunc(crawl.Id, "crawl_frontier")
 }
 
 // DeleteProjectCrawls deletes the project's crawl data
@@ -481,14 +715,18 @@ func (ds *Datastore) DeleteProjectCrawls(p *models.Project) {
 	}
 }
 
-// Deletes all crawls that are unfinished and have the issues_end field set to null.
+// Deletes all crawls that are unfinished and have the issues_end field set to null,
+// except the ones that can be resumed from a frontier checkpoint. Mobile crawls don't have
+// issues, so they are unfinished only if they don't have the end field set.
 // It cleans up the crawl data for each unfinished crawl before deleting it.
 func (ds *Datastore) DeleteUnfinishedCrawls() int {
 	query := `
 		SELECT
 			crawls.id
 		FROM crawls
-		WHERE crawls.issues_end IS NULL
+		INNER JOIN projects ON projects.id = crawls.project_id
+		WHERE crawls.issues_end IS NULL AND NOT (` + resumableCrawl + `)
+			AND (crawls.parent_id IS NULL OR crawls.end IS NULL)
 	`
 	count := 0
 
@@ -0,0 +1,66 @@
+package datastore
+
+import (
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SaveRobotstxtFile stores the robots.txt file of a host used in the crawl.
+// Files of hosts that have already been stored in the crawl are ignored.
+func (ds *Datastore) SaveRobotstxtFile(c *models.Crawl, f *models.RobotstxtFile) error {
+	query := `
+		INSERT IGNORE INTO robotstxt_files (
+			crawl_id,
+			host,
+			status_code,
+			draft,
+			body
+		)
+		VALUES (?, ?, ?, ?, ?)`
+
+	_, err := ds.db.Exec(
+		query,
+		c.Id,
+		Truncate(f.Host, 256),
+		f.StatusCode,
+		f.Draft,
+		f.Body,
+	)
+
+	return err
+}
+
+// GetRobotstxtFiles returns the robots.txt files used in the crawl sorted by host.
+func (ds *Datastore) GetRobotstxtFiles(crawlId int64) []models.RobotstxtFile {
+	query := `
+		SELECT
+			host,
+			status_code,
+			draft,
+			body
+		FROM robotstxt_files
+		WHERE crawl_id = ?
+		ORDER BY host`
+
+	files := []models.RobotstxtFile{}
+
+	rows, err := ds.db.Query(query, crawlId)
+	if err != nil {
+		log.Println(err)
+		return files
+	}
+
+	for rows.Next() {
+		f := models.RobotstxtFile{}
+		err := rows.Scan(&f.Host, &f.StatusCode, &f.Draft, &f.Body)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		files = append(files, f)
+	}
+
+	return files
+}
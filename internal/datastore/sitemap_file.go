@@ -0,0 +1,114 @@
+package datastore
+
+import (
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SaveSitemapFile stores the audit of a sitemap file fetched in the crawl.
+// Files that have already been stored in the crawl are ignored.
+func (ds *Datastore) SaveSitemapFile(c *models.Crawl, f *models.SitemapFile) error {
+	query := `
+		INSERT IGNORE INTO sitemap_files (
+			crawl_id,
+			url,
+			url_hash,
+			index_url,
+			is_index,
+			status_code,
+			content_type,
+			compressed,
+			size,
+			urls,
+			invalid_lastmod,
+			future_lastmod,
+			external_urls,
+			duplicate_urls,
+			fetch_error,
+			parse_error
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := ds.db.Exec(
+		query,
+		c.Id,
+		Truncate(f.URL, 2048),
+		Hash(f.URL),
+		Truncate(f.Index, 2048),
+		f.IsIndex,
+		f.StatusCode,
+		Truncate(f.ContentType, 256),
+		f.Compressed,
+		f.Size,
+		f.URLs,
+		f.InvalidLastmod,
+		f.FutureLastmod,
+		f.ExternalURLs,
+		f.DuplicateURLs,
+		Truncate(f.FetchError, 64),
+		Truncate(f.ParseError, 512),
+	)
+
+	return err
+}
+
+// GetSitemapFiles returns the sitemap files fetched in the crawl.
+// The files that are not listed in a sitemap index are sorted first.
+func (ds *Datastore) GetSitemapFiles(crawlId int64) []models.SitemapFile {
+	query := `
+		SELECT
+			url,
+			index_url,
+			is_index,
+			status_code,
+			content_type,
+			compressed,
+			size,
+			urls,
+			invalid_lastmod,
+			future_lastmod,
+			external_urls,
+			duplicate_urls,
+			fetch_error,
+			parse_error
+		FROM sitemap_files
+		WHERE crawl_id = ?
+		ORDER BY index_url != '', url`
+
+	files := []models.SitemapFile{}
+
+	rows, err := ds.db.Query(query, crawlId)
+	if err != nil {
+		log.Println(err)
+		return files
+	}
+
+	for rows.Next() {
+		f := models.SitemapFile{}
+		err := rows.Scan(
+			&f.URL,
+			&f.Index,
+			&f.IsIndex,
+			&f.StatusCode,
+			&f.ContentType,
+			&f.Compressed,
+			&f.Size,
+			&f.URLs,
+			&f.InvalidLastmod,
+			&f.FutureLastmod,
+			&f.ExternalURLs,
+			&f.DuplicateURLs,
+			&f.FetchError,
+			&f.ParseError,
+		)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		files = append(files, f)
+	}
+
+	return files
+}
@@ -0,0 +1,97 @@
+package datastore
+
+import (
+	"log"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SaveTLSCertificate stores the certificate of a host found in the crawl.
+// Certificates of hosts that have already been stored in the crawl are ignored.
+func (ds *Datastore) SaveTLSCertificate(c *models.Crawl, t *models.TLSCertificate) error {
+	query := `
+		INSERT IGNORE INTO tls_certificates (
+			crawl_id,
+			host,
+			subject,
+			issuer,
+			not_after,
+			dns_names,
+			valid_chain,
+			chain_error,
+			hostname_match,
+			version
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := ds.db.Exec(
+		query,
+		c.Id,
+		Truncate(t.Host, 256),
+		Truncate(t.Subject, 512),
+		Truncate(t.Issuer, 512),
+		t.NotAfter,
+		strings.Join(t.DNSNames, "\n"),
+		t.ValidChain,
+		Truncate(t.ChainError, 512),
+		t.HostnameMatch,
+		t.Version,
+	)
+
+	return err
+}
+
+// GetTLSCertificates returns the certificates of the hosts found in the crawl sorted by host.
+func (ds *Datastore) GetTLSCertificates(crawlId int64) []models.TLSCertificate {
+	query := `
+		SELECT
+			host,
+			subject,
+			issuer,
+			not_after,
+			dns_names,
+			valid_chain,
+			chain_error,
+			hostname_match,
+			version
+		FROM tls_certificates
+		WHERE crawl_id = ?
+		ORDER BY host`
+
+	certificates := []models.TLSCertificate{}
+
+	rows, err := ds.db.Query(query, crawlId)
+	if err != nil {
+		log.Println(err)
+		return certificates
+	}
+
+	for rows.Next() {
+		t := models.TLSCertificate{}
+		var dnsNames string
+		err := rows.Scan(
+			&t.Host,
+			&t.Subject,
+			&t.Issuer,
+			&t.NotAfter,
+			&dnsNames,
+			&t.ValidChain,
+			&t.ChainError,
+			&t.HostnameMatch,
+			&t.Version,
+		)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		if dnsNames != "" {
+			t.DNSNames = strings.Split(dnsNames, "\n")
+		}
+
+		certificates = append(certificates, t)
+	}
+
+	return certificates
+}
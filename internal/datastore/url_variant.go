@@ -0,0 +1,17 @@
+package datastore
+
+import (
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SaveURLVariant stores an URL variant found in the crawl.
+// Variants that have already been stored in the crawl are ignored.
+func (ds *Datastore) SaveURLVariant(c *models.Crawl, v *models.URLVariant) error {
+	query := `
+		INSERT IGNORE INTO url_variants (crawl_id, url, url_hash, variant, variant_hash)
+		VALUES (?, ?, ?, ?, ?)`
+
+	_, err := ds.db.Exec(query, c.Id, v.URL, Hash(v.URL), Truncate(v.Variant, 2048), Hash(v.Variant))
+
+	return err
+}
@@ -0,0 +1,264 @@
+package diff
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Page report fields that are compared between crawls.
+const (
+	StatusCode  = "status_code"
+	Title       = "title"
+	Description = "description"
+	Canonical   = "canonical"
+	Robots      = "robots"
+	Words       = "words"
+	Links       = "links"
+	Hreflang    = "hreflang"
+)
+
+type Storage interface {
+	GetRetainedCrawls(*models.Project) []models.Crawl
+	CountNewPageReports(cid, prevCid int64) int
+	CountPageReportChanges(cid, prevCid int64, field string) int
+	CountNewIssues(cid, prevCid int64) map[string]int
+	FindNewPageReports(cid, prevCid int64) <-chan *models.PageReport
+	FindPageReportChanges(cid, prevCid int64, field string) <-chan *PageChange
+	FindNewIssues(cid, prevCid int64) <-chan *IssueChange
+	GetMobileCrawl(*models.Crawl) (models.Crawl, error)
+}
+
+// PageChange contains the value of a page report field in two different crawls.
+type PageChange struct {
+	URL      string
+	Previous string
+	Current  string
+}
+
+// IssueChange contains the URL of a page report that has an issue
+// in one crawl but not in the other.
+type IssueChange struct {
+	URL       string
+	ErrorType string
+}
+
+// IssueDiff contains the number of pages affected by an issue type that are new
+// in the current crawl or that have been resolved since the previous crawl.
+type IssueDiff struct {
+	ErrorType string
+	New       int
+	Resolved  int
+}
+
+// Diff contains the differences between two crawls.
+type Diff struct {
+	Crawl              models.Crawl
+	PreviousCrawl      models.Crawl
+	Added              int
+	Removed            int
+	StatusCodeChanges  int
+	TitleChanges       int
+	DescriptionChanges int
+	CanonicalChanges   int
+	Issues             []IssueDiff
+}
+
+// MobileDiff contains the differences between a crawl and the crawl of the
+// same URLs with the mobile user agent.
+type MobileDiff struct {
+	Crawl             models.Crawl
+	MobileCrawl       models.Crawl
+	Missing           int // URLs crawled with the desktop user agent but not with the mobile one
+	StatusCodeChanges int
+	TitleChanges      int
+	CanonicalChanges  int
+	RobotsChanges     int
+	LinksChanges      int
+	WordsChanges      int
+	HreflangChanges   int
+}
+
+type Service struct {
+	store Storage
+}
+
+func NewService(s Storage) *Service {
+	return &Service{
+		store: s,
+	}
+}
+
+// Returns the project's crawls that can be compared, ordered from newest to oldest.
+func (s *Service) GetCrawls(p *models.Project) []models.Crawl {
+	return s.store.GetRetainedCrawls(p)
+}
+
+// GetDiff returns a Diff with the changes found in crawl since the previous crawl.
+func (s *Service) GetDiff(crawl, previous *models.Crawl) *Diff {
+	d := &Diff{
+		Crawl:              *crawl,
+		PreviousCrawl:      *previous,
+		Added:              s.store.CountNewPageReports(crawl.Id, previous.Id),
+		Removed:            s.store.CountNewPageReports(previous.Id, crawl.Id),
+		StatusCodeChanges:  s.store.CountPageReportChanges(crawl.Id, previous.Id, StatusCode),
+		TitleChanges:       s.store.CountPageReportChanges(crawl.Id, previous.Id, Title),
+		DescriptionChanges: s.store.CountPageReportChanges(crawl.Id, previous.Id, Description),
+		CanonicalChanges:   s.store.CountPageReportChanges(crawl.Id, previous.Id, Canonical),
+	}
+
+	newIssues := s.store.CountNewIssues(crawl.Id, previous.Id)
+	resolvedIssues := s.store.CountNewIssues(previous.Id, crawl.Id)
+
+	issues := make(map[string]*IssueDiff)
+	for k, v := range newIssues {
+		issues[k] = &IssueDiff{ErrorType: k, New: v}
+	}
+
+	for k, v := range resolvedIssues {
+		if _, ok := issues[k]; !ok {
+			issues[k] = &IssueDiff{ErrorType: k}
+		}
+		issues[k].Resolved = v
+	}
+
+	for _, v := range issues {
+		d.Issues = append(d.Issues, *v)
+	}
+
+	sort.Slice(d.Issues, func(i, j int) bool {
+		return d.Issues[i].ErrorType < d.Issues[j].ErrorType
+	})
+
+	return d
+}
+
+// GetMobileDiff returns a MobileDiff with the differences found between the crawl and its
+// mobile crawl. It returns an error if the crawl doesn't have a mobile crawl.
+func (s *Service) GetMobileDiff(crawl *models.Crawl) (*MobileDiff, error) {
+	mobile, err := s.store.GetMobileCrawl(crawl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MobileDiff{
+		Crawl:             *crawl,
+		MobileCrawl:       mobile,
+		Missing:           s.store.CountNewPageReports(crawl.Id, mobile.Id),
+		StatusCodeChanges: s.store.CountPageReportChanges(mobile.Id, crawl.Id, StatusCode),
+		TitleChanges:      s.store.CountPageReportChanges(mobile.Id, crawl.Id, Title),
+		CanonicalChanges:  s.store.CountPageReportChanges(mobile.Id, crawl.Id, Canonical),
+		RobotsChanges:     s.store.CountPageReportChanges(mobile.Id, crawl.Id, Robots),
+		LinksChanges:      s.store.CountPageReportChanges(mobile.Id, crawl.Id, Links),
+		WordsChanges:      s.store.CountPageReportChanges(mobile.Id, crawl.Id, Words),
+		HreflangChanges:   s.store.CountPageReportChanges(mobile.Id, crawl.Id, Hreflang),
+	}, nil
+}
+
+// ExportMobileChanges exports the pages with a different value in the field in the crawl
+// and its mobile crawl as a CSV file.
+func (s *Service) ExportMobileChanges(f io.Writer, crawl, mobile *models.Crawl, field string) {
+	s.exportChanges(f, s.store.FindPageReportChanges(mobile.Id, crawl.Id, field), "Desktop", "Mobile")
+}
+
+// Export the URLs of the pages crawled with the desktop user agent that were not crawled
+// with the mobile user agent as a CSV file.
+func (s *Service) ExportMobileMissing(f io.Writer, crawl, mobile *models.Crawl) {
+	s.exportPageReports(f, s.store.FindNewPageReports(crawl.Id, mobile.Id))
+}
+
+// Export the URLs of the pages added since the previous crawl as a CSV file.
+func (s *Service) ExportAdded(f io.Writer, crawl, previous *models.Crawl) {
+	s.exportPageReports(f, s.store.FindNewPageReports(crawl.Id, previous.Id))
+}
+
+// Export the URLs of the pages removed since the previous crawl as a CSV file.
+func (s *Service) ExportRemoved(f io.Writer, crawl, previous *models.Crawl) {
+	s.exportPageReports(f, s.store.FindNewPageReports(previous.Id, crawl.Id))
+}
+
+// Export the pages with a different status code as a CSV file.
+func (s *Service) ExportStatusCodeChanges(f io.Writer, crawl, previous *models.Crawl) {
+	s.exportChanges(f, s.store.FindPageReportChanges(crawl.Id, previous.Id, StatusCode), "Previous", "Current")
+}
+
+// Export the pages with a different title as a CSV file.
+func (s *Service) ExportTitleChanges(f io.Writer, crawl, previous *models.Crawl) {
+	s.exportChanges(f, s.store.FindPageReportChanges(crawl.Id, previous.Id, Title), "Previous", "Current")
+}
+
+// Export the pages with a different meta description as a CSV file.
+func (s *Service) ExportDescriptionChanges(f io.Writer, crawl, previous *models.Crawl) {
+	s.exportChanges(f, s.store.FindPageReportChanges(crawl.Id, previous.Id, Description), "Previous", "Current")
+}
+
+// Export the pages with a different canonical URL as a CSV file.
+func (s *Service) ExportCanonicalChanges(f io.Writer, crawl, previous *models.Crawl) {
+	s.exportChanges(f, s.store.FindPageReportChanges(crawl.Id, previous.Id, Canonical), "Previous", "Current")
+}
+
+// Export the new and resolved issues as a CSV file.
+func (s *Service) ExportIssues(f io.Writer, crawl, previous *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"URL",
+		"Issue",
+		"Change",
+	})
+
+	for v := range s.store.FindNewIssues(crawl.Id, previous.Id) {
+		w.Write([]string{v.URL, v.ErrorType, "New"})
+	}
+
+	for v := range s.store.FindNewIssues(previous.Id, crawl.Id) {
+		w.Write([]string{v.URL, v.ErrorType, "Resolved"})
+	}
+
+	w.Flush()
+}
+
+func (s *Service) exportPageReports(f io.Writer, prStream <-chan *models.PageReport) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"URL",
+		"Status Code",
+		"Title",
+	})
+
+	for v := range prStream {
+		w.Write([]string{
+			v.URL,
+			strconv.Itoa(v.StatusCode),
+			v.Title,
+		})
+	}
+
+	w.Flush()
+}
+
+// Exports the changes as a CSV file. The previous and current parameters are
+// the column names of the previous and current values.
+func (s *Service) exportChanges(f io.Writer, cStream <-chan *PageChange, previous, current string) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"URL",
+		previous,
+		current,
+	})
+
+	for v := range cStream {
+		w.Write([]string{
+			v.URL,
+			v.Previous,
+			v.Current,
+		})
+	}
+
+	w.Flush()
+}
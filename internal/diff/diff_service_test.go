@@ -0,0 +1,131 @@
+package diff_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/diff"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+const (
+	crawlId         = 2
+	previousCrawlId = 1
+	mobileCrawlId   = 3
+)
+
+type storage struct{}
+
+func (s *storage) GetRetainedCrawls(*models.Project) []models.Crawl {
+	return []models.Crawl{{Id: crawlId}, {Id: previousCrawlId}}
+}
+func (s *storage) CountNewPageReports(cid, prevCid int64) int {
+	if cid == crawlId {
+		return 3
+	}
+
+	return 1
+}
+func (s *storage) CountPageReportChanges(cid, prevCid int64, field string) int {
+	if field == diff.Title {
+		return 2
+	}
+
+	return 0
+}
+func (s *storage) CountNewIssues(cid, prevCid int64) map[string]int {
+	if cid == crawlId {
+		return map[string]int{"ERROR_40x": 2, "ERROR_EMPTY_TITLE": 1}
+	}
+
+	return map[string]int{"ERROR_40x": 1, "ERROR_30x": 4}
+}
+func (s *storage) FindNewPageReports(cid, prevCid int64) <-chan *models.PageReport {
+	c := make(chan *models.PageReport)
+	close(c)
+	return c
+}
+func (s *storage) FindPageReportChanges(cid, prevCid int64, field string) <-chan *diff.PageChange {
+	c := make(chan *diff.PageChange)
+	close(c)
+	return c
+}
+func (s *storage) FindNewIssues(cid, prevCid int64) <-chan *diff.IssueChange {
+	c := make(chan *diff.IssueChange)
+	close(c)
+	return c
+}
+func (s *storage) GetMobileCrawl(c *models.Crawl) (models.Crawl, error) {
+	if c.Id != crawlId {
+		return models.Crawl{}, errors.New("mobile crawl not found")
+	}
+
+	return models.Crawl{Id: mobileCrawlId}, nil
+}
+
+func TestGetDiff(t *testing.T) {
+	service := diff.NewService(&storage{})
+
+	d := service.GetDiff(&models.Crawl{Id: crawlId}, &models.Crawl{Id: previousCrawlId})
+
+	if d.Added != 3 {
+		t.Errorf("Added %d != 3", d.Added)
+	}
+
+	if d.Removed != 1 {
+		t.Errorf("Removed %d != 1", d.Removed)
+	}
+
+	if d.TitleChanges != 2 {
+		t.Errorf("TitleChanges %d != 2", d.TitleChanges)
+	}
+
+	if d.StatusCodeChanges != 0 {
+		t.Errorf("StatusCodeChanges %d != 0", d.StatusCodeChanges)
+	}
+
+	expected := []diff.IssueDiff{
+		{ErrorType: "ERROR_30x", New: 0, Resolved: 4},
+		{ErrorType: "ERROR_40x", New: 2, Resolved: 1},
+		{ErrorType: "ERROR_EMPTY_TITLE", New: 1, Resolved: 0},
+	}
+
+	if len(d.Issues) != len(expected) {
+		t.Fatalf("Issues len %d != %d", len(d.Issues), len(expected))
+	}
+
+	for i, v := range expected {
+		if d.Issues[i] != v {
+			t.Errorf("Issues %d: %+v != %+v", i, d.Issues[i], v)
+		}
+	}
+}
+
+func TestGetMobileDiff(t *testing.T) {
+	service := diff.NewService(&storage{})
+
+	d, err := service.GetMobileDiff(&models.Crawl{Id: crawlId})
+	if err != nil {
+		t.Fatalf("GetMobileDiff: %v", err)
+	}
+
+	if d.MobileCrawl.Id != mobileCrawlId {
+		t.Errorf("MobileCrawl %d != %d", d.MobileCrawl.Id, mobileCrawlId)
+	}
+
+	if d.Missing != 3 {
+		t.Errorf("Missing %d != 3", d.Missing)
+	}
+
+	if d.TitleChanges != 2 {
+		t.Errorf("TitleChanges %d != 2", d.TitleChanges)
+	}
+
+	if d.LinksChanges != 0 {
+		t.Errorf("LinksChanges %d != 0", d.LinksChanges)
+	}
+
+	if _, err := service.GetMobileDiff(&models.Crawl{Id: previousCrawlId}); err == nil {
+		t.Error("GetMobileDiff: error expected for a crawl without mobile crawl")
+	}
+}
@@ -3,6 +3,7 @@ package export
 import (
 	"encoding/csv"
 	"io"
+	"strconv"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 )
@@ -51,6 +52,31 @@ type Hreflang struct {
 	HreflangLang string
 }
 
+type RedirectHop struct {
+	Origin     string
+	Hop        int
+	URL        string
+	StatusCode int
+	Location   string
+}
+
+type ResourceStatus struct {
+	Origin     string
+	Type       string
+	URL        string
+	StatusCode int
+	FetchError string
+}
+
+type ExternalLinkStatus struct {
+	Origin      string
+	Destination string
+	Text        string
+	StatusCode  int
+	RedirectURL string
+	FetchError  string
+}
+
 type Store interface {
 	ExportLinks(*models.Crawl) <-chan *Link
 	ExportExternalLinks(*models.Crawl) <-chan *Link
@@ -61,6 +87,9 @@ type Store interface {
 	ExportAudios(crawl *models.Crawl) <-chan *Audio
 	ExportVideos(crawl *models.Crawl) <-chan *Video
 	ExportHreflangs(crawl *models.Crawl) <-chan *Hreflang
+	ExportRedirectHops(crawl *models.Crawl) <-chan *RedirectHop
+	ExportExternalLinkStatuses(crawl *models.Crawl) <-chan *ExternalLinkStatus
+	ExportResourceStatuses(crawl *models.Crawl) <-chan *ResourceStatus
 }
 
 type Exporter struct {
@@ -271,3 +300,86 @@ func (e *Exporter) ExportHreflangs(f io.Writer, crawl *models.Crawl) {
 
 	w.Flush()
 }
+
+// Export all redirect chains as a CSV file
+func (e *Exporter) ExportRedirectHops(f io.Writer, crawl *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"Origin",
+		"Hop",
+		"URL",
+		"Status Code",
+		"Location",
+	})
+
+	vStream := e.store.ExportRedirectHops(crawl)
+
+	for v := range vStream {
+		w.Write([]string{
+			v.Origin,
+			strconv.Itoa(v.Hop),
+			v.URL,
+			strconv.Itoa(v.StatusCode),
+			v.Location,
+		})
+	}
+
+	w.Flush()
+}
+
+// Export all checked external links as a CSV file, including the status of the destination URL
+func (e *Exporter) ExportExternalLinkStatuses(f io.Writer, crawl *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"Origin",
+		"Destination",
+		"Text",
+		"Status Code",
+		"Redirect URL",
+		"Fetch Error",
+	})
+
+	vStream := e.store.ExportExternalLinkStatuses(crawl)
+
+	for v := range vStream {
+		w.Write([]string{
+			v.Origin,
+			v.Destination,
+			v.Text,
+			strconv.Itoa(v.StatusCode),
+			v.RedirectURL,
+			v.FetchError,
+		})
+	}
+
+	w.Flush()
+}
+
+// Export the status of all the images, scripts, styles, audios and videos of each page as a CSV file
+func (e *Exporter) ExportResourceStatuses(f io.Writer, crawl *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"Origin",
+		"Type",
+		"URL",
+		"Status Code",
+		"Fetch Error",
+	})
+
+	vStream := e.store.ExportResourceStatuses(crawl)
+
+	for v := range vStream {
+		w.Write([]string{
+			v.Origin,
+			v.Type,
+			v.URL,
+			strconv.Itoa(v.StatusCode),
+			v.FetchError,
+		})
+	}
+
+	w.Flush()
+}
@@ -2,6 +2,8 @@ package html_parser
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
@@ -12,11 +14,15 @@ import (
 	"strings"
 
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/simhash"
 
 	"golang.org/x/net/html"
 	"golang.org/x/text/language"
 )
 
+// Matches punctuation and symbols, which are removed from the visible text.
+var punctuationRegex = regexp.MustCompile(`[\p{P}\p{S}]+`)
+
 const (
 	// MaxBodySize is the limit of the retrieved response body in bytes.
 	// The default value for MaxBodySize is 10MB (10 * 1024 * 1024 bytes).
@@ -24,7 +30,8 @@ const (
 )
 
 // Create a new PageReport from an http.Response.
-func NewFromHTTPResponse(r *http.Response) (*models.PageReport, *html.Node, error) {
+// The robots directives are evaluated for the bot token, or only the generic ones if it is empty.
+func NewFromHTTPResponse(r *http.Response, token string) (*models.PageReport, *html.Node, error) {
 	defer r.Body.Close()
 
 	var bodyReader io.Reader = r.Body
@@ -35,11 +42,12 @@ func NewFromHTTPResponse(r *http.Response) (*models.PageReport, *html.Node, erro
 		return &models.PageReport{}, nil, err
 	}
 
-	return New(r.Request.URL, r.StatusCode, &r.Header, b)
+	return New(r.Request.URL, r.StatusCode, &r.Header, b, token)
 }
 
 // Return a new PageReport.
-func New(u *url.URL, status int, headers *http.Header, body []byte) (*models.PageReport, *html.Node, error) {
+// The robots directives are evaluated for the bot token, or only the generic ones if it is empty.
+func New(u *url.URL, status int, headers *http.Header, body []byte, token string) (*models.PageReport, *html.Node, error) {
 	parser, err := newParser(u, headers, body)
 	if err != nil {
 		return nil, nil, err
@@ -72,9 +80,8 @@ func New(u *url.URL, status int, headers *http.Header, body []byte) (*models.Pag
 		pageReport.Description = parser.htmlMetaDescription()
 		pageReport.Refresh = parser.htmlMetaRefresh()
 		pageReport.RedirectURL = parser.htmlMetaRefreshURL()
-		pageReport.Robots = parser.robots()
-		pageReport.Noindex = strings.Contains(pageReport.Robots, "noindex")
-		pageReport.Nofollow = strings.Contains(pageReport.Robots, "nofollow")
+		pageReport.Robots = parser.robots(token)
+		setRobotsDirectives(&pageReport, pageReport.Robots)
 		pageReport.H1 = parser.htmlH1()
 		pageReport.H2 = parser.htmlH2()
 		pageReport.Canonical = parser.canonical()
@@ -102,6 +109,12 @@ func New(u *url.URL, status int, headers *http.Header, body []byte) (*models.Pag
 		if bnode != nil {
 			pageReport.Words = countWords(bnode)
 			pageReport.ValidHeadings = headingOrderIsValid(bnode)
+
+			if text := visibleText(bnode); text != "" {
+				hash := sha256.Sum256([]byte(text))
+				pageReport.ContentHash = hex.EncodeToString(hash[:])
+				pageReport.SimHash = simhash.Fingerprint(text)
+			}
 		}
 	}
 
@@ -152,6 +165,39 @@ func countWords(n *html.Node) int {
 	return len(strings.Fields(t))
 }
 
+// Returns the visible text of an HTML node, lowercased and without punctuation,
+// with its words separated by a single space.
+func visibleText(n *html.Node) string {
+	hidden := map[string]bool{"script": true, "style": true, "noscript": true, "template": true}
+
+	var output func(*strings.Builder, *html.Node)
+	output = func(b *strings.Builder, n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+			return
+		case html.CommentNode:
+			return
+		case html.ElementNode:
+			if hidden[n.Data] {
+				return
+			}
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			output(b, child)
+		}
+	}
+
+	var b strings.Builder
+	output(&b, n)
+
+	t := punctuationRegex.ReplaceAllString(strings.ToLower(b.String()), " ")
+
+	return strings.Join(strings.Fields(t), " ")
+}
+
 // Check if the H headings order is valid.
 func headingOrderIsValid(n *html.Node) bool {
 	headings := [6]string{"h1", "h2", "h3", "h4", "h5", "h6"}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stjudewashere/seonaut/internal/html_parser"
+	"github.com/stjudewashere/seonaut/internal/models"
 )
 
 const (
@@ -29,7 +30,7 @@ func TestNewPageReport(t *testing.T) {
 		"Content-Type": []string{contentType},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -66,7 +67,7 @@ func TestNewRedirectPageReport(t *testing.T) {
 		"Content-Type": []string{"text/html"},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -96,7 +97,7 @@ func TestPageReportHTML(t *testing.T) {
 		log.Fatal(err)
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -199,7 +200,7 @@ func TestMultipleCanonicalTags(t *testing.T) {
 			</head>
 		`)
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -227,7 +228,7 @@ func TestCanonicalTagInBody(t *testing.T) {
 			</body>
 		`)
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -250,7 +251,7 @@ func TestNoindex(t *testing.T) {
 		"Content-Type": []string{"text/html"},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -278,7 +279,7 @@ func TestContentLanguage(t *testing.T) {
 		"Content-Type":     []string{"text/html"},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -307,7 +308,7 @@ func TestHreflangHeaders(t *testing.T) {
 		"Content-Type": []string{"text/html"},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -348,7 +349,7 @@ func TestCanonicalHeaders(t *testing.T) {
 		"Content-Type": []string{"text/html"},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -377,7 +378,7 @@ func TestRelativeCanonicalHeaders(t *testing.T) {
 		"Content-Type": []string{"text/html"},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -399,7 +400,7 @@ func TestNoBodyTag(t *testing.T) {
 		"Content-Type": []string{"text/html"},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -423,7 +424,7 @@ func TestInvalidLang(t *testing.T) {
 		"Content-Type": []string{contentType},
 	}
 
-	pageReport, _, err := html_parser.New(u, statusCode, &headers, body)
+	pageReport, _, err := html_parser.New(u, statusCode, &headers, body, "")
 	if err != nil {
 		t.Error(err)
 	}
@@ -432,3 +433,104 @@ func TestInvalidLang(t *testing.T) {
 		t.Error("ValidLang != false")
 	}
 }
+
+func TestContentHash(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	statusCode := 200
+	headers := http.Header{
+		"Content-Type": []string{"text/html"},
+	}
+
+	parse := func(body string) *models.PageReport {
+		pageReport, _, err := html_parser.New(u, statusCode, &headers, []byte(body), "")
+		if err != nil {
+			t.Error(err)
+		}
+
+		return pageReport
+	}
+
+	a := parse(`<html><body><h1>Hello, World!</h1><script>var a = 1;</script><p>Some text.</p></body></html>`)
+	b := parse(`<html><body><div><h1>hello world</h1></div><!-- comment --><p>some   text</p><style>p {}</style></body></html>`)
+	c := parse(`<html><body><h1>Hello</h1><p>Other text.</p></body></html>`)
+
+	if a.ContentHash == "" {
+		t.Error("ContentHash is empty")
+	}
+
+	if a.ContentHash != b.ContentHash {
+		t.Errorf("ContentHash: %s != %s", a.ContentHash, b.ContentHash)
+	}
+
+	if a.SimHash != b.SimHash {
+		t.Errorf("SimHash: %d != %d", a.SimHash, b.SimHash)
+	}
+
+	if a.ContentHash == c.ContentHash {
+		t.Error("ContentHash of different content is equal")
+	}
+
+	empty := parse(`<html><body><script>var a = 1;</script></body></html>`)
+	if empty.ContentHash != "" || empty.SimHash != 0 {
+		t.Errorf("Empty content: %s %d", empty.ContentHash, empty.SimHash)
+	}
+}
+
+func TestRobotsDirectives(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	body := []byte(`<html><head>
+		<meta name="robots" content="max-snippet:50">
+		<meta name="Googlebot" content="nosnippet, max-snippet:20">
+		<meta name="bingbot" content="noindex">
+	</head></html>`)
+
+	headers := http.Header{
+		"Content-Type": []string{"text/html"},
+		"X-Robots-Tag": []string{
+			"unavailable_after: Friday, 25-Jun-10 15:00:00 UTC",
+			"googlebot: nofollow, unavailable_after: 2010-06-01",
+			"bingbot: none",
+		},
+	}
+
+	table := []struct {
+		token            string
+		noindex          bool
+		nofollow         bool
+		nosnippet        bool
+		maxSnippet       int
+		unavailableAfter string
+	}{
+		{"", false, false, false, 50, "2010-06-25"},
+		{"googlebot", false, true, true, 20, "2010-06-01"},
+		{"Bingbot", true, true, false, 50, "2010-06-25"},
+	}
+
+	for _, tc := range table {
+		pageReport, _, err := html_parser.New(u, 200, &headers, body, tc.token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if pageReport.Noindex != tc.noindex || pageReport.Nofollow != tc.nofollow || pageReport.Nosnippet != tc.nosnippet {
+			t.Errorf("%q noindex, nofollow, nosnippet: %v %v %v", tc.token, pageReport.Noindex, pageReport.Nofollow, pageReport.Nosnippet)
+		}
+
+		if pageReport.MaxSnippet != tc.maxSnippet {
+			t.Errorf("%q MaxSnippet: %d want %d", tc.token, pageReport.MaxSnippet, tc.maxSnippet)
+		}
+
+		unavailableAfter := pageReport.UnavailableAfter.Time.Format("2006-01-02")
+		if !pageReport.UnavailableAfter.Valid || unavailableAfter != tc.unavailableAfter {
+			t.Errorf("%q UnavailableAfter: %s want %s", tc.token, unavailableAfter, tc.unavailableAfter)
+		}
+	}
+}
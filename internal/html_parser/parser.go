@@ -58,16 +58,13 @@ func (p *Parser) lang() string {
 	return lang
 }
 
-// Returns the document robots settings.
-// It will return the html meta robots content if it is not empty
-// otherwise it returns the content of the X-Robots-Tag header.
-func (p *Parser) robots() string {
-	robots := p.htmlMetaRobots()
-	if robots == "" {
-		return p.headersRobots()
-	}
+// Returns the document robots settings for the bot token.
+// It combines the contents of the html meta robots tags and the X-Robots-Tag headers,
+// including the ones scoped to the bot token, separated by commas.
+func (p *Parser) robots(token string) string {
+	robots := append(p.htmlMetaRobots(token), p.headersRobots(token)...)
 
-	return robots
+	return strings.Join(robots, ", ")
 }
 
 // Returns the document canonical settings.
@@ -161,15 +158,29 @@ func (p *Parser) htmlMetaRefreshURL() string {
 	return ""
 }
 
-// The robots meta provides information to crawlers
+// The robots meta provides information to crawlers, it can also be scoped to the bot token
 // ex. <meta name="robots" content="noindex, nofollow" />
-func (p *Parser) htmlMetaRobots() string {
-	robots, err := htmlquery.Query(p.doc, "//meta[@name=\"robots\"]/@content")
-	if err != nil || robots == nil {
-		return ""
+// ex. <meta name="googlebot" content="nosnippet" />
+func (p *Parser) htmlMetaRobots(token string) []string {
+	robots := []string{}
+
+	metas, err := htmlquery.QueryAll(p.doc, "//meta[@name]")
+	if err != nil {
+		return robots
 	}
 
-	return htmlquery.SelectAttr(robots, "content")
+	for _, m := range metas {
+		name := strings.TrimSpace(htmlquery.SelectAttr(m, "name"))
+		if !strings.EqualFold(name, "robots") && (token == "" || !strings.EqualFold(name, token)) {
+			continue
+		}
+
+		if content := strings.TrimSpace(htmlquery.SelectAttr(m, "content")); content != "" {
+			robots = append(robots, content)
+		}
+	}
+
+	return robots
 }
 
 // H1 heading title
@@ -544,9 +555,22 @@ func (p *Parser) headersLang() string {
 	return ""
 }
 
-// Returns the contents of the X-Robots-Tag header.
-func (p *Parser) headersRobots() string {
-	return p.Headers.Get("X-Robots-Tag")
+// Returns the contents of the X-Robots-Tag headers that apply to the bot token.
+// Headers scoped to other bots, as in "X-Robots-Tag: bingbot: noindex", are ignored.
+func (p *Parser) headersRobots(token string) []string {
+	robots := []string{}
+	for _, v := range p.Headers.Values("X-Robots-Tag") {
+		bot, directives, scoped := robotsHeaderScope(v)
+		if scoped && (token == "" || !strings.EqualFold(bot, token)) {
+			continue
+		}
+
+		if directives != "" {
+			robots = append(robots, directives)
+		}
+	}
+
+	return robots
 }
 
 // Return the contents of the HTTP Location header.
@@ -0,0 +1,110 @@
+package html_parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Robots directives that have a value, so they are not mistaken for a bot name
+// in the X-Robots-Tag headers.
+var valuedRobotsDirectives = map[string]bool{
+	"max-snippet":       true,
+	"max-image-preview": true,
+	"max-video-preview": true,
+	"unavailable_after": true,
+}
+
+// Layouts of the dates allowed in the unavailable_after directive.
+var unavailableAfterLayouts = []string{
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC850,
+	time.RFC822,
+	time.RFC822Z,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006",
+}
+
+// Splits an X-Robots-Tag header value scoped to a bot, as in "googlebot: noindex, nofollow",
+// into the bot name and its directives. It returns false if the value is not scoped to a bot.
+func robotsHeaderScope(v string) (string, string, bool) {
+	bot, directives, found := strings.Cut(v, ":")
+	bot = strings.TrimSpace(bot)
+	if !found || strings.Contains(bot, ",") || valuedRobotsDirectives[strings.ToLower(bot)] {
+		return "", strings.TrimSpace(v), false
+	}
+
+	return bot, strings.TrimSpace(directives), true
+}
+
+// Sets the robots directives of the PageReport from its comma separated robots settings.
+// If a directive is found more than once the most restrictive value is used.
+func setRobotsDirectives(pageReport *models.PageReport, robots string) {
+	parts := strings.Split(robots, ",")
+	for i := 0; i < len(parts); i++ {
+		name, value, _ := strings.Cut(parts[i], ":")
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "noindex":
+			pageReport.Noindex = true
+		case "nofollow":
+			pageReport.Nofollow = true
+		case "none":
+			pageReport.Noindex = true
+			pageReport.Nofollow = true
+		case "nosnippet":
+			pageReport.Nosnippet = true
+		case "max-snippet":
+			// A value of -1 means there is no limit and 0 is the same as nosnippet.
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				continue
+			}
+
+			if n == 0 {
+				pageReport.Nosnippet = true
+			} else if pageReport.MaxSnippet == 0 || n < pageReport.MaxSnippet {
+				pageReport.MaxSnippet = n
+			}
+		case "unavailable_after":
+			// Dates may contain commas, so the next parts are added to the value until it is parsed.
+			for j := i; j < len(parts); j++ {
+				if j > i {
+					value += "," + parts[j]
+				}
+
+				t, ok := parseUnavailableAfter(value)
+				if !ok {
+					continue
+				}
+
+				if !pageReport.UnavailableAfter.Valid || t.Before(pageReport.UnavailableAfter.Time) {
+					pageReport.UnavailableAfter.Time = t
+					pageReport.UnavailableAfter.Valid = true
+				}
+
+				i = j
+				break
+			}
+		}
+	}
+}
+
+// Parses the date of an unavailable_after directive in any of the allowed layouts.
+// It returns false if the date is not valid.
+func parseUnavailableAfter(v string) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	for _, layout := range unavailableAfterLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
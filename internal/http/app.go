@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/stjudewashere/seonaut/internal/crawler"
+	"github.com/stjudewashere/seonaut/internal/diff"
 	"github.com/stjudewashere/seonaut/internal/export"
 	"github.com/stjudewashere/seonaut/internal/issue"
 	"github.com/stjudewashere/seonaut/internal/project"
@@ -39,6 +40,7 @@ type Services struct {
 	ReportManager      *report_manager.ReportManager
 	PubSubBroker       *pubsub.Broker
 	ExportService      *export.Exporter
+	DiffService        *diff.Service
 }
 
 // App is the server application, and it contains all the needed services to handle requests.
@@ -55,6 +57,7 @@ type App struct {
 	projectViewService *projectview.Service
 	pubsubBroker       *pubsub.Broker
 	exportService      *export.Exporter
+	diffService        *diff.Service
 }
 
 // PageView is the data structure used to render the html templates.
@@ -103,6 +106,7 @@ func NewApp(c *HTTPServerConfig, s *Services) *App {
 		projectViewService: s.ProjectViewService,
 		pubsubBroker:       s.PubSubBroker,
 		exportService:      s.ExportService,
+		diffService:        s.DiffService,
 	}
 }
 
@@ -122,7 +126,11 @@ func (app *App) Run() {
 	http.HandleFunc("/crawl", app.requireAuth(app.handleCrawl))
 	http.HandleFunc("/crawl-live", app.requireAuth(app.handleCrawlLive))
 	http.HandleFunc("/crawl-auth", app.requireAuth(app.handleCrawlAuth))
+	http.HandleFunc("/crawl-list", app.requireAuth(app.handleCrawlList))
 	http.HandleFunc("/crawl-ws", app.requireAuth(app.handleCrawlWs))
+	http.HandleFunc("/crawl-cancel", app.requireAuth(app.handleCrawlCancel))
+	http.HandleFunc("/crawl-pause", app.requireAuth(app.handleCrawlPause))
+	http.HandleFunc("/crawl-resume", app.requireAuth(app.handleCrawlResume))
 	http.HandleFunc("/issues", app.requireAuth(app.handleIssues))
 	http.HandleFunc("/issues/view", app.requireAuth(app.handleIssuesView))
 	http.HandleFunc("/dashboard", app.requireAuth(app.handleDashboard))
@@ -134,6 +142,12 @@ func (app *App) Run() {
 	http.HandleFunc("/signout", app.requireAuth(app.handleSignout))
 	http.HandleFunc("/account", app.requireAuth(app.handleAccount))
 	http.HandleFunc("/explorer", app.requireAuth(app.handleExplorer))
+	http.HandleFunc("/diff", app.requireAuth(app.handleDiff))
+	http.HandleFunc("/diff/download", app.requireAuth(app.handleDiffExport))
+	http.HandleFunc("/mobile-diff", app.requireAuth(app.handleMobileDiff))
+	http.HandleFunc("/mobile-diff/download", app.requireAuth(app.handleMobileDiffExport))
+	http.HandleFunc("/sitemaps", app.requireAuth(app.handleSitemaps))
+	http.HandleFunc("/robotstxt", app.requireAuth(app.handleRobotstxt))
 	http.HandleFunc("/signup", app.handleSignup)
 	http.HandleFunc("/signin", app.handleSignin)
 
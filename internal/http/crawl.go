@@ -2,13 +2,16 @@ package http
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/stjudewashere/seonaut/internal/crawler"
 	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/pubsub"
 
@@ -16,6 +19,9 @@ import (
 )
 
 const (
+	// Max size in bytes of the URL lists uploaded for list mode crawls.
+	maxURLListSize = 10 << 20
+
 	writeWait  = 10 * time.Second
 	pongWait   = 60 * time.Second
 	pingPeriod = (pongWait * 9) / 10
@@ -23,8 +29,8 @@ const (
 
 // handleCrawl handles the crawling of a project.
 // It expects a query parameter "pid" containing the project ID to be crawled.
-// In case the project requieres BasicAuth it will redirect the user to the BasicAuth
-// credentials URL. Otherwise, it starts a new crawler.
+// In case the project requieres credentials that are not stored, such as the BasicAuth ones,
+// it will redirect the user to the credentials URL. Otherwise, it starts a new crawler.
 func (app *App) handleCrawl(w http.ResponseWriter, r *http.Request) {
 	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
 	if err != nil {
@@ -47,8 +53,8 @@ func (app *App) handleCrawl(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if p.BasicAuth == true {
-		http.Redirect(w, r, "/crawl-auth?id="+strconv.Itoa(pid), http.StatusSeeOther)
+	if requiresCredentials(p) {
+		http.Redirect(w, r, "/crawl-auth?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 
 		return
 	}
@@ -58,10 +64,10 @@ func (app *App) handleCrawl(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/crawl-live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 }
 
-// handleCrawlAuth handles the crawling of a project with BasicAuth.
+// handleCrawlAuth handles the crawling of a project that requires credentials.
 // It expects a query parameter "pid" containing the project ID to be crawled.
-// A form will be presented to the user to input the BasicAuth credentials, once the
-// form is submitted a crawler with BasicAuth is started.
+// A form will be presented to the user to input the BasicAuth credentials, the login form data
+// or the proxy credentials, once the form is submitted a crawler with the credentials is started.
 //
 // The function handles both GET and POST HTTP methods.
 // GET: Renders the auth form.
@@ -96,13 +102,13 @@ func (app *App) handleCrawlAuth(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		p.BasicAuth = true
-		p.AuthUser = r.FormValue("username")
-		p.AuthPass = r.FormValue("password")
+		setCredentials(r, &p)
 
 		go app.startCrawler(p)
 
 		http.Redirect(w, r, "/crawl-live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
+
+		return
 	}
 
 	pageView := &PageView{
@@ -113,6 +119,88 @@ func (app *App) handleCrawlAuth(w http.ResponseWriter, r *http.Request) {
 	app.renderer.RenderTemplate(w, "crawl_auth", pageView)
 }
 
+// handleCrawlList handles the crawling of a project in list mode.
+// It expects a query parameter "pid" containing the project ID to be crawled.
+// A form will be presented to the user to paste or upload the list of URLs. Once the form is
+// submitted a crawler that only crawls the URLs in the list, without following links, is started.
+// If any URL is not in the project's domain the form is shown again with an error.
+//
+// The function handles both GET and POST HTTP methods.
+// GET: Renders the list form.
+// POST: Processes the list form data and starts the crawler.
+func (app *App) handleCrawlList(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+
+		return
+	}
+
+	p, err := app.projectService.FindProject(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	data := &struct {
+		Project models.Project
+		Error   bool
+		Ignored []string
+	}{
+		Project: p,
+	}
+
+	pageView := &PageView{
+		PageTitle: "CRAWL_LIST_VIEW",
+		User:      *user,
+		Data:      data,
+	}
+
+	if r.Method == http.MethodPost {
+		err := r.ParseMultipartForm(maxURLListSize)
+		if err != nil {
+			data.Error = true
+			app.renderer.RenderTemplate(w, "crawl_list", pageView)
+
+			return
+		}
+
+		var list io.Reader = strings.NewReader(r.FormValue("urls") + "\n")
+		file, _, err := r.FormFile("file")
+		if err == nil {
+			defer file.Close()
+			list = io.MultiReader(list, io.LimitReader(file, maxURLListSize))
+		}
+
+		urls, ignored, err := crawler.ParseURLList(list, p)
+		if err != nil || len(urls) == 0 || len(ignored) > 0 {
+			data.Error = true
+			data.Ignored = ignored
+			app.renderer.RenderTemplate(w, "crawl_list", pageView)
+
+			return
+		}
+
+		setCredentials(r, &p)
+
+		go app.startListCrawler(p, urls)
+
+		http.Redirect(w, r, "/crawl-live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
+
+		return
+	}
+
+	app.renderer.RenderTemplate(w, "crawl_list", pageView)
+}
+
 // handleCrawlLive handles the request for the live crawling of a project.
 // It expects a query parameter "pid" containing the project ID to be crawled.
 func (app *App) handleCrawlLive(w http.ResponseWriter, r *http.Request) {
@@ -154,9 +242,11 @@ func (app *App) handleCrawlLive(w http.ResponseWriter, r *http.Request) {
 		Data: struct {
 			Project models.Project
 			Secure  bool
+			Paused  bool
 		}{
 			Project: pv.Project,
 			Secure:  configURL.Scheme == "https",
+			Paused:  app.crawlerService.CrawlerPaused(pv.Project),
 		},
 		User:      *user,
 		PageTitle: "CRAWL_LIVE",
@@ -239,7 +329,12 @@ func (app *App) handleCrawlWs(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if pubsubMessage.Name == "CrawlEnd" {
-			msg := pubsubMessage.Data.(int)
+			msg := pubsubMessage.Data.(*models.CrawlEndMessage)
+			wsMessage.Data = msg
+		}
+
+		if pubsubMessage.Name == "LinkCheck" {
+			msg := pubsubMessage.Data.(*models.LinkCheckMessage)
 			wsMessage.Data = msg
 		}
 
@@ -278,6 +373,65 @@ func (app *App) handleCrawlWs(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleCrawlCancel handles the cancellation of a project's running crawler.
+// It expects a query parameter "pid" containing the project ID.
+// The crawl is ended with the URLs crawled so far and its report is created as usual.
+func (app *App) handleCrawlCancel(w http.ResponseWriter, r *http.Request) {
+	app.controlCrawler(w, r, app.crawlerService.CancelCrawler)
+}
+
+// handleCrawlPause handles the pausing of a project's running crawler.
+// It expects a query parameter "pid" containing the project ID.
+func (app *App) handleCrawlPause(w http.ResponseWriter, r *http.Request) {
+	app.controlCrawler(w, r, app.crawlerService.PauseCrawler)
+}
+
+// handleCrawlResume handles the resuming of a project's paused crawler.
+// It expects a query parameter "pid" containing the project ID.
+func (app *App) handleCrawlResume(w http.ResponseWriter, r *http.Request) {
+	app.controlCrawler(w, r, app.crawlerService.ResumeCrawler)
+}
+
+// Helper function to run the crawler control function f on the project's crawler.
+// Only POST requests are allowed. It responds with a NoContent status code on success
+// and with a Conflict status code if the project is not being crawled.
+func (app *App) controlCrawler(w http.ResponseWriter, r *http.Request, f func(models.Project) error) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	p, err := app.projectService.FindProject(pid, user.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	if err := f(p); err != nil {
+		w.WriteHeader(http.StatusConflict)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Helper function to start crawling a project.
 func (app *App) startCrawler(p models.Project) {
 	log.Printf("Crawling %s\n", p.URL)
@@ -289,9 +443,46 @@ func (app *App) startCrawler(p models.Project) {
 	}
 
 	log.Printf("Crawled %d pages at %s\n", crawl.TotalURLs, p.URL)
+}
+
+// startListCrawler starts a crawler in list mode and logs the crawl's results.
+func (app *App) startListCrawler(p models.Project, urls []string) {
+	log.Printf("Crawling list of %d URLs at %s\n", len(urls), p.URL)
+	crawl, err := app.crawlerService.StartListCrawler(p, urls)
+	if err != nil {
+		log.Printf("StartListCrawler: %s %v\n", p.URL, err)
+
+		return
+	}
+
+	log.Printf("Crawled %d pages at %s\n", crawl.TotalURLs, p.URL)
+}
+
+// requiresCredentials returns true if the project's crawls need credentials that are not
+// stored, so they must be provided by the user every time the project is crawled.
+func requiresCredentials(p models.Project) bool {
+	return p.BasicAuth || p.LoginURL != "" || p.ProxyAuth
+}
+
+// setCredentials sets the project's credentials from the crawl form.
+// The proxy credentials are added to the project's proxy URL.
+func setCredentials(r *http.Request, p *models.Project) {
+	if p.BasicAuth {
+		p.AuthUser = r.FormValue("username")
+		p.AuthPass = r.FormValue("password")
+	}
 
-	app.pubsubBroker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "IssuesInit"})
-	app.reportManager.CreateMultipageIssues(crawl)
-	app.issueService.SaveCrawlIssuesCount(crawl)
-	app.pubsubBroker.Publish(fmt.Sprintf("crawl-%d", p.Id), &pubsub.Message{Name: "CrawlEnd", Data: crawl.TotalURLs})
+	if p.LoginURL != "" {
+		p.LoginData = strings.TrimSpace(r.FormValue("login_data"))
+	}
+
+	if p.ProxyAuth && r.FormValue("proxy_username") != "" {
+		proxyURL, err := url.Parse(p.ProxyURL)
+		if err != nil {
+			return
+		}
+
+		proxyURL.User = url.UserPassword(r.FormValue("proxy_username"), r.FormValue("proxy_password"))
+		p.ProxyURL = proxyURL.String()
+	}
 }
@@ -29,6 +29,10 @@ type DashboardView struct {
 	AltCount          *report.AltCount
 	SchemeCount       *report.SchemeCount
 	StatusCodeByDepth []report.StatusCodeByDepth
+	ResponseTimes     []report.ResponseTimeRange
+	SlowestPages      []models.PageReport
+	MediaTypeBytes    []report.MediaTypeBytes
+	TLSCertificates   []models.TLSCertificate
 }
 
 // handleDashboard handles the dashboard of a project.
@@ -70,6 +74,10 @@ func (app *App) handleDashboard(w http.ResponseWriter, r *http.Request) {
 		AltCount:          app.reportService.GetImageAltCount(pv.Crawl.Id),
 		SchemeCount:       app.reportService.GetSchemeCount(pv.Crawl.Id),
 		StatusCodeByDepth: app.reportService.GetStatusCodeByDepth(pv.Crawl.Id),
+		ResponseTimes:     app.reportService.GetResponseTimeRanges(pv.Crawl.Id),
+		SlowestPages:      app.reportService.GetSlowestPageReports(pv.Crawl.Id),
+		MediaTypeBytes:    app.reportService.GetBytesByMediaType(pv.Crawl.Id),
+		TLSCertificates:   app.reportService.GetTLSCertificates(pv.Crawl.Id),
 	}
 
 	pageView := &PageView{
@@ -0,0 +1,253 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/diff"
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/projectview"
+)
+
+type DiffView struct {
+	ProjectView *projectview.ProjectView
+	Crawls      []models.Crawl
+	Diff        *diff.Diff
+}
+
+// handleDiff handles the comparison of the project's last crawl with a previous crawl.
+// It expects a query parameter "pid" containing the project ID, and an optional "prev"
+// parameter with the ID of the previous crawl. It defaults to the crawl before the last one.
+func (app *App) handleDiff(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+
+		return
+	}
+
+	pv, err := app.projectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	if pv.Crawl.TotalURLs == 0 {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	crawls, previous := app.previousCrawls(pv, r.URL.Query().Get("prev"))
+
+	data := DiffView{
+		ProjectView: pv,
+		Crawls:      crawls,
+	}
+
+	if previous != nil {
+		data.Diff = app.diffService.GetDiff(&pv.Crawl, previous)
+	}
+
+	app.renderer.RenderTemplate(w, "diff", &PageView{
+		Data:      data,
+		User:      *user,
+		PageTitle: "CRAWL_DIFF",
+	})
+}
+
+// handleDiffExport exports the differences between the project's last crawl and a previous crawl.
+// The URL query parameter t specifies the type of differences to be exported.
+func (app *App) handleDiffExport(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+
+		return
+	}
+
+	pv, err := app.projectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	_, previous := app.previousCrawls(pv, r.URL.Query().Get("prev"))
+	if previous == nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	t := r.URL.Query().Get("t")
+
+	m := map[string]func(io.Writer, *models.Crawl, *models.Crawl){
+		"added":       app.diffService.ExportAdded,
+		"removed":     app.diffService.ExportRemoved,
+		"status":      app.diffService.ExportStatusCodeChanges,
+		"title":       app.diffService.ExportTitleChanges,
+		"description": app.diffService.ExportDescriptionChanges,
+		"canonical":   app.diffService.ExportCanonicalChanges,
+		"issues":      app.diffService.ExportIssues,
+	}
+
+	e, ok := m[t]
+	if !ok {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	fileName := pv.Project.Host + " diff " + t + " " + time.Now().Format("2006-01-02")
+
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.csv\"", fileName))
+
+	e(w, &pv.Crawl, previous)
+}
+
+// handleMobileDiff handles the comparison of the project's last crawl with its mobile crawl.
+// It expects a query parameter "pid" containing the project ID.
+func (app *App) handleMobileDiff(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+
+		return
+	}
+
+	pv, err := app.projectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	data := &struct {
+		ProjectView *projectview.ProjectView
+		MobileDiff  *diff.MobileDiff
+	}{
+		ProjectView: pv,
+	}
+
+	data.MobileDiff, _ = app.diffService.GetMobileDiff(&pv.Crawl)
+
+	app.renderer.RenderTemplate(w, "mobile_diff", &PageView{
+		Data:      data,
+		User:      *user,
+		PageTitle: "MOBILE_DIFF",
+	})
+}
+
+// handleMobileDiffExport exports the differences between the project's last crawl and its mobile crawl.
+// The URL query parameter t specifies the field with the differences to be exported.
+func (app *App) handleMobileDiffExport(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+
+		return
+	}
+
+	pv, err := app.projectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	d, err := app.diffService.GetMobileDiff(&pv.Crawl)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	t := r.URL.Query().Get("t")
+
+	fields := map[string]bool{
+		diff.StatusCode: true,
+		diff.Title:      true,
+		diff.Canonical:  true,
+		diff.Robots:     true,
+		diff.Links:      true,
+		diff.Words:      true,
+		diff.Hreflang:   true,
+	}
+
+	if t != "missing" && !fields[t] {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	fileName := pv.Project.Host + " mobile " + t + " " + time.Now().Format("2006-01-02")
+
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.csv\"", fileName))
+
+	if t == "missing" {
+		app.diffService.ExportMobileMissing(w, &d.Crawl, &d.MobileCrawl)
+
+		return
+	}
+
+	app.diffService.ExportMobileChanges(w, &d.Crawl, &d.MobileCrawl, t)
+}
+
+// Returns the crawls that can be compared with the project's last crawl and the
+// crawl specified by the prev crawl id. If prev is not valid it returns the most recent one.
+func (app *App) previousCrawls(pv *projectview.ProjectView, prev string) ([]models.Crawl, *models.Crawl) {
+	crawls := []models.Crawl{}
+	for _, c := range app.diffService.GetCrawls(&pv.Project) {
+		if c.Id != pv.Crawl.Id {
+			crawls = append(crawls, c)
+		}
+	}
+
+	if len(crawls) == 0 {
+		return crawls, nil
+	}
+
+	cid, err := strconv.ParseInt(prev, 10, 64)
+	if err == nil {
+		for i := range crawls {
+			if crawls[i].Id == cid {
+				return crawls, &crawls[i]
+			}
+		}
+	}
+
+	return crawls, &crawls[0]
+}
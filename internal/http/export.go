@@ -157,6 +157,9 @@ func (app *App) handleExportResources(w http.ResponseWriter, r *http.Request) {
 		"audios":    app.exportService.ExportAudios,
 		"videos":    app.exportService.ExportVideos,
 		"hreflangs": app.exportService.ExportHreflangs,
+		"redirects": app.exportService.ExportRedirectHops,
+		"outbound":  app.exportService.ExportExternalLinkStatuses,
+		"resources": app.exportService.ExportResourceStatuses,
 	}
 
 	e, ok := m[t]
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/project"
 	"github.com/stjudewashere/seonaut/internal/projectview"
 )
 
@@ -98,6 +99,23 @@ func (app *App) handleProjectAdd(w http.ResponseWriter, r *http.Request) {
 			basicAuth = false
 		}
 
+		renderJS, err := strconv.ParseBool(r.FormValue("render_js"))
+		if err != nil {
+			renderJS = false
+		}
+
+		mobileCrawl, err := strconv.ParseBool(r.FormValue("mobile_crawl"))
+		if err != nil {
+			mobileCrawl = false
+		}
+
+		checkLinks, err := strconv.ParseBool(r.FormValue("check_external_links"))
+		if err != nil {
+			checkLinks = false
+		}
+
+		keepCrawls := formInt(r, "keep_crawls", project.DefaultKeepCrawls)
+
 		parsedURL, err := url.ParseRequestURI(strings.TrimSpace(u))
 		if err != nil {
 			data.Error = true
@@ -114,6 +132,15 @@ func (app *App) handleProjectAdd(w http.ResponseWriter, r *http.Request) {
 			CrawlSitemap:    crawlSitemap,
 			AllowSubdomains: allowSubdomains,
 			BasicAuth:       basicAuth,
+			KeepCrawls:      keepCrawls,
+			MaxPageReports:  formInt(r, "max_pagereports", project.DefaultMaxPageReports),
+			MaxDepth:        formInt(r, "max_depth", project.DefaultMaxDepth),
+			CrawlThreads:    formInt(r, "crawl_threads", project.DefaultCrawlThreads),
+			CrawlDelay:      formInt(r, "crawl_delay", project.DefaultCrawlDelay),
+			CrawlTimeout:    formInt(r, "crawl_timeout", project.DefaultCrawlTimeout),
+			RenderJS:        renderJS,
+			MobileCrawl:     mobileCrawl,
+			CheckLinks:      checkLinks,
 		}
 
 		err = app.projectService.SaveProject(project, user.Id)
@@ -235,8 +262,62 @@ func (app *App) handleProjectEdit(w http.ResponseWriter, r *http.Request) {
 			p.BasicAuth = false
 		}
 
+		p.RenderJS, err = strconv.ParseBool(r.FormValue("render_js"))
+		if err != nil {
+			p.RenderJS = false
+		}
+
+		p.MobileCrawl, err = strconv.ParseBool(r.FormValue("mobile_crawl"))
+		if err != nil {
+			p.MobileCrawl = false
+		}
+
+		p.CheckLinks, err = strconv.ParseBool(r.FormValue("check_external_links"))
+		if err != nil {
+			p.CheckLinks = false
+		}
+
+		p.KeepCrawls = formInt(r, "keep_crawls", project.DefaultKeepCrawls)
+		p.MaxPageReports = formInt(r, "max_pagereports", project.DefaultMaxPageReports)
+		p.MaxDepth = formInt(r, "max_depth", project.DefaultMaxDepth)
+		p.CrawlThreads = formInt(r, "crawl_threads", project.DefaultCrawlThreads)
+		p.CrawlDelay = formInt(r, "crawl_delay", project.DefaultCrawlDelay)
+		p.CrawlTimeout = formInt(r, "crawl_timeout", project.DefaultCrawlTimeout)
+
+		p.Schedule = strings.TrimSpace(r.FormValue("crawl_schedule"))
+
+		p.CrawlHeaders = strings.TrimSpace(r.FormValue("crawl_headers"))
+		p.UseCookies, err = strconv.ParseBool(r.FormValue("use_cookies"))
+		if err != nil {
+			p.UseCookies = false
+		}
+
+		p.LoginURL = strings.TrimSpace(r.FormValue("login_url"))
+		p.ProxyURL = strings.TrimSpace(r.FormValue("proxy_url"))
+		p.ProxyAuth, err = strconv.ParseBool(r.FormValue("proxy_auth"))
+		if err != nil {
+			p.ProxyAuth = false
+		}
+
+		p.IncludeRules = strings.TrimSpace(r.FormValue("include_rules"))
+		p.ExcludeRules = strings.TrimSpace(r.FormValue("exclude_rules"))
+		p.RobotsDraft = strings.TrimSpace(r.FormValue("robots_draft"))
+		p.RobotsToken = strings.TrimSpace(r.FormValue("robots_token"))
+
+		p.NormalizeURLs, err = strconv.ParseBool(r.FormValue("normalize_urls"))
+		if err != nil {
+			p.NormalizeURLs = false
+		}
+
+		p.StripParams = strings.TrimSpace(r.FormValue("strip_params"))
+		p.IgnoreParams, err = strconv.ParseBool(r.FormValue("ignore_params"))
+		if err != nil {
+			p.IgnoreParams = false
+		}
+
 		err = app.projectService.UpdateProject(&p)
 		if err != nil {
+			data.Project = p
 			data.Error = true
 			app.renderer.RenderTemplate(w, "project_edit", pageView)
 
@@ -250,3 +331,13 @@ func (app *App) handleProjectEdit(w http.ResponseWriter, r *http.Request) {
 
 	app.renderer.RenderTemplate(w, "project_edit", pageView)
 }
+
+// Returns the integer value of the form field, or the default value d if it is not a valid integer.
+func formInt(r *http.Request, name string, d int) int {
+	v, err := strconv.Atoi(r.FormValue(name))
+	if err != nil {
+		return d
+	}
+
+	return v
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/projectview"
+	"github.com/stjudewashere/seonaut/internal/report"
+)
+
+// handleRobotstxt handles the robots.txt view of a project.
+// It expects a query parameter "pid" containing the project id and shows the robots.txt files
+// used in the project's last crawl. If the "urls" parameter is set, each of its lines is tested
+// against the files for the user agent token in the "agent" parameter, which defaults to the
+// project's bot token.
+func (app *App) handleRobotstxt(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+
+		return
+	}
+
+	pv, err := app.projectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	agent := strings.TrimSpace(r.URL.Query().Get("agent"))
+	if agent == "" {
+		agent = pv.Project.RobotsToken
+	}
+	urls := r.URL.Query().Get("urls")
+
+	data := &struct {
+		ProjectView *projectview.ProjectView
+		Files       []models.RobotstxtFile
+		Agent       string
+		URLs        string
+		Tests       []report.RobotstxtTest
+	}{
+		ProjectView: pv,
+		Files:       app.reportService.GetRobotstxtFiles(pv.Crawl.Id),
+		Agent:       agent,
+		URLs:        urls,
+		Tests:       app.reportService.TestRobotstxt(pv.Crawl.Id, agent, strings.Split(urls, "\n")),
+	}
+
+	app.renderer.RenderTemplate(w, "robotstxt", &PageView{
+		Data:      data,
+		User:      *user,
+		PageTitle: "ROBOTSTXT",
+	})
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/stjudewashere/seonaut/internal/projectview"
+	"github.com/stjudewashere/seonaut/internal/report"
+)
+
+// handleSitemaps handles the sitemaps view of a project.
+// It expects a query parameter "pid" containing the project id and lists the sitemap
+// files fetched in the project's last crawl with their stats and issues.
+func (app *App) handleSitemaps(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	user, ok := app.userService.GetUserFromContext(r.Context())
+	if ok == false {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+
+		return
+	}
+
+	pv, err := app.projectViewService.GetProjectView(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+
+		return
+	}
+
+	data := &struct {
+		ProjectView  *projectview.ProjectView
+		SitemapFiles []report.SitemapFileView
+	}{
+		ProjectView:  pv,
+		SitemapFiles: app.reportService.GetSitemapFiles(pv.Crawl.Id),
+	}
+
+	app.renderer.RenderTemplate(w, "sitemaps", &PageView{
+		Data:      data,
+		User:      *user,
+		PageTitle: "SITEMAPS",
+	})
+}
@@ -0,0 +1,127 @@
+package httpcrawler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"sync"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// ErrDeprecatedTLS is returned by the requests to servers that only support TLS versions
+// older than TLS 1.2, which browsers don't support anymore.
+var ErrDeprecatedTLS = errors.New("tls: deprecated TLS version")
+
+// CertificateStore records the certificate of each host the client connects to.
+// It verifies the certificates itself so the details of the invalid ones are also recorded.
+type CertificateStore struct {
+	lock         sync.RWMutex
+	certificates map[string]*models.TLSCertificate
+}
+
+func NewCertificateStore() *CertificateStore {
+	return &CertificateStore{
+		certificates: make(map[string]*models.TLSCertificate),
+	}
+}
+
+// Returns the TLS config of a client that records the certificates in the store.
+// Connections to servers using TLS 1.0 and 1.1 are allowed so their version can be
+// recorded, but VerifyConnection fails so the requests fail as with the default config.
+func (s *CertificateStore) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS10,
+		// The certificates are verified in VerifyConnection.
+		InsecureSkipVerify: true,
+		VerifyConnection:   s.VerifyConnection,
+	}
+}
+
+// VerifyConnection records the certificate of the connection's host the first time
+// it connects to it, and verifies the certificate chain and the hostname as the default
+// TLS config does. The hostname is only verified if the server name is set, which is
+// not the case if the host is an IP address. It returns ErrDeprecatedTLS if the
+// TLS version is older than TLS 1.2.
+func (s *CertificateStore) VerifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server sent no certificates")
+	}
+
+	leaf := cs.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+
+	_, chainErr := leaf.Verify(x509.VerifyOptions{Intermediates: intermediates})
+
+	var hostnameErr error
+	if cs.ServerName != "" {
+		hostnameErr = leaf.VerifyHostname(cs.ServerName)
+		s.add(newTLSCertificate(cs, chainErr, hostnameErr))
+	}
+
+	if chainErr != nil {
+		return &tls.CertificateVerificationError{UnverifiedCertificates: cs.PeerCertificates, Err: chainErr}
+	}
+
+	if hostnameErr != nil {
+		return &tls.CertificateVerificationError{UnverifiedCertificates: cs.PeerCertificates, Err: hostnameErr}
+	}
+
+	if cs.Version < tls.VersionTLS12 {
+		return ErrDeprecatedTLS
+	}
+
+	return nil
+}
+
+// Returns the recorded certificate of the host, or nil if there is none.
+func (s *CertificateStore) Get(host string) *models.TLSCertificate {
+	if s == nil {
+		return nil
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.certificates[host]
+}
+
+// Adds the certificate to the store unless the host's certificate is already recorded.
+func (s *CertificateStore) add(c *models.TLSCertificate) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.certificates[c.Host]; !ok {
+		s.certificates[c.Host] = c
+	}
+}
+
+// Returns a TLSCertificate with the details of the leaf certificate of the connection.
+func newTLSCertificate(cs tls.ConnectionState, chainErr, hostnameErr error) *models.TLSCertificate {
+	leaf := cs.PeerCertificates[0]
+
+	issuer := leaf.Issuer.CommonName
+	if issuer == "" {
+		issuer = leaf.Issuer.String()
+	}
+
+	c := &models.TLSCertificate{
+		Host:          cs.ServerName,
+		Subject:       leaf.Subject.CommonName,
+		Issuer:        issuer,
+		NotAfter:      leaf.NotAfter,
+		DNSNames:      leaf.DNSNames,
+		ValidChain:    chainErr == nil,
+		HostnameMatch: hostnameErr == nil,
+		Version:       tls.VersionName(cs.Version),
+	}
+
+	if chainErr != nil {
+		c.ChainError = chainErr.Error()
+	}
+
+	return c
+}
@@ -0,0 +1,75 @@
+package httpcrawler_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+)
+
+func TestCertificateStore(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	table := []struct {
+		host          string
+		version       uint16
+		hostnameMatch bool
+	}{
+		{"example.com", tls.VersionTLS13, true},
+		{"seonaut.org", tls.VersionTLS10, false},
+	}
+
+	store := httpcrawler.NewCertificateStore()
+	for _, v := range table {
+		err := store.VerifyConnection(tls.ConnectionState{
+			ServerName:       v.host,
+			Version:          v.version,
+			PeerCertificates: []*x509.Certificate{srv.Certificate()},
+		})
+
+		// The test server's certificate is not signed by a trusted authority.
+		if httpcrawler.FetchError(err) != httpcrawler.FetchErrorTLS {
+			t.Errorf("%s: error %v should be a TLS error", v.host, err)
+		}
+
+		c := store.Get(v.host)
+		if c == nil {
+			t.Fatalf("%s: certificate not recorded", v.host)
+		}
+
+		if c.ValidChain || c.ChainError == "" {
+			t.Errorf("%s: chain should not be valid", v.host)
+		}
+
+		if c.HostnameMatch != v.hostnameMatch {
+			t.Errorf("%s: hostname match %v != %v", v.host, c.HostnameMatch, v.hostnameMatch)
+		}
+
+		if c.Version != tls.VersionName(v.version) {
+			t.Errorf("%s: version %s != %s", v.host, c.Version, tls.VersionName(v.version))
+		}
+	}
+
+	if store.Get("example.org") != nil {
+		t.Errorf("certificate of a host that was not connected should be nil")
+	}
+}
+
+func TestClientCertificates(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	client := httpcrawler.NewClient(&httpcrawler.ClientOptions{
+		Certificates: httpcrawler.NewCertificateStore(),
+	})
+
+	// Requests to hosts with untrusted certificates fail as with the default client.
+	_, err := client.Get(srv.URL)
+	if httpcrawler.FetchError(err) != httpcrawler.FetchErrorTLS {
+		t.Errorf("error %v should be a TLS error", err)
+	}
+}
@@ -0,0 +1,85 @@
+package httpcrawler
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// Types of errors of the URLs that could not be fetched.
+const (
+	FetchErrorTimeout           = "timeout"
+	FetchErrorDNS               = "DNS failure"
+	FetchErrorTLS               = "TLS error"
+	FetchErrorConnectionRefused = "connection refused"
+	FetchErrorConnectionReset   = "connection reset"
+	FetchErrorNetwork           = "network error"
+	FetchErrorEncoding          = "unsupported encoding"
+)
+
+// FetchError returns the type of error of a failed request.
+func FetchError(err error) string {
+	var encodingErr *UnsupportedEncodingError
+	if errors.As(err, &encodingErr) {
+		return FetchErrorEncoding
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return FetchErrorDNS
+	}
+
+	if isTLSError(err) {
+		return FetchErrorTLS
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return FetchErrorConnectionRefused
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) {
+		return FetchErrorConnectionReset
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return FetchErrorTimeout
+	}
+
+	return FetchErrorNetwork
+}
+
+// Returns true if the request should be retried. Network errors are retried unless the
+// host does not exist or there is a TLS error. Responses with a 429 or 5xx status code are retried.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false
+		}
+
+		return !isTLSError(err)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// Returns true if the error is caused by the TLS handshake or an invalid certificate.
+func isTLSError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	return errors.Is(err, ErrDeprecatedTLS) ||
+		errors.As(err, &certErr) ||
+		errors.As(err, &recordErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
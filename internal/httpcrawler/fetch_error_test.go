@@ -0,0 +1,97 @@
+package httpcrawler_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+)
+
+// RetryClient returns a 503 response until the number of failures is reached.
+type RetryClient struct {
+	MockClient
+	failures int
+	requests int
+}
+
+func (c *RetryClient) GetContext(ctx context.Context, u string) (*http.Response, error) {
+	c.requests++
+	if c.requests <= c.failures {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestFetchError(t *testing.T) {
+	opErr := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://example.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: err}}
+	}
+
+	table := []struct {
+		err  error
+		want string
+	}{
+		{&url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", Name: "example.com", IsNotFound: true}}, httpcrawler.FetchErrorDNS},
+		{&url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, httpcrawler.FetchErrorTLS},
+		{opErr(os.NewSyscallError("connect", syscall.ECONNREFUSED)), httpcrawler.FetchErrorConnectionRefused},
+		{opErr(os.NewSyscallError("read", syscall.ECONNRESET)), httpcrawler.FetchErrorConnectionReset},
+		{&url.Error{Op: "Get", Err: context.DeadlineExceeded}, httpcrawler.FetchErrorTimeout},
+		{opErr(os.ErrDeadlineExceeded), httpcrawler.FetchErrorTimeout},
+		{fmt.Errorf("wrapped: %w", errors.New("unexpected EOF")), httpcrawler.FetchErrorNetwork},
+		{&httpcrawler.UnsupportedEncodingError{Encoding: "zstd"}, httpcrawler.FetchErrorEncoding},
+	}
+
+	for _, v := range table {
+		if got := httpcrawler.FetchError(v.err); got != v.want {
+			t.Errorf("FetchError(%v) = %q, want %q", v.err, got, v.want)
+		}
+	}
+}
+
+func TestHttpCrawlerRetries(t *testing.T) {
+	table := []struct {
+		failures   int
+		retries    int
+		requests   int
+		statusCode int
+	}{
+		{failures: 2, retries: 2, requests: 3, statusCode: http.StatusOK},
+		{failures: 3, retries: 2, requests: 3, statusCode: http.StatusServiceUnavailable},
+		{failures: 1, retries: 0, requests: 1, statusCode: http.StatusServiceUnavailable},
+	}
+
+	for _, v := range table {
+		urlStream := make(chan *httpcrawler.RequestMessage, 1)
+		urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com"}
+
+		client := &RetryClient{failures: v.failures}
+		crawler := httpcrawler.New(client, urlStream, &httpcrawler.Options{
+			Threads:    1,
+			Retries:    v.retries,
+			RetryDelay: time.Millisecond,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := <-crawler.Crawl(ctx)
+		cancel()
+		close(urlStream)
+
+		if result.Response.StatusCode != v.statusCode {
+			t.Errorf("status code %d != %d", result.Response.StatusCode, v.statusCode)
+		}
+
+		if client.requests != v.requests {
+			t.Errorf("requests %d != %d", client.requests, v.requests)
+		}
+	}
+}
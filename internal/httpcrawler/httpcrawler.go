@@ -1,24 +1,54 @@
 package httpcrawler
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"math/rand"
+	"mime"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
 )
 
 const (
-	// Random delay in milliseconds.
+	// Default random delay in milliseconds.
 	// A random delay up to this value is introduced before new HTTP requests.
 	randomDelay = 1500
 
-	// Number of threads a queue will use to crawl a project.
+	// Default number of threads a queue will use to crawl a project.
 	consumerThreads = 2
+
+	// Max number of redirects followed to record a redirect chain.
+	maxRedirectHops = 10
 )
 
+// Options for the HttpCrawler. A zero Threads value is replaced by the default, while a zero
+// RandomDelay disables the delay. A nil Options uses the default Threads and RandomDelay.
+// If RateLimiter is not nil, it limits the rate of the requests to each host.
+// Failed requests are retried up to Retries times, waiting RetryDelay before the first
+// retry and doubling it in each of the following retries.
+// If Renderer is not nil, HTML responses are also rendered executing their JavaScript.
+// The Session options are passed to the Renderer so it sends the same credentials, headers
+// and cookies as the client.
+// If FollowRedirect is not nil, the redirect chains only request the URLs it returns true for.
+type Options struct {
+	Threads        int
+	RandomDelay    time.Duration
+	RateLimiter    *RateLimiter
+	Retries        int
+	RetryDelay     time.Duration
+	Renderer       Renderer
+	Session        *ClientOptions
+	FollowRedirect func(u string) bool
+}
+
 type Client interface {
 	Get(u string) (*http.Response, error)
+	GetContext(ctx context.Context, u string) (*http.Response, error)
 	Head(u string) (*http.Response, error)
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -27,6 +57,7 @@ type HttpCrawler struct {
 	urlStream <-chan *RequestMessage
 	rStream   chan *ResponseMessage
 	client    Client
+	options   *Options
 }
 
 type RequestMessage struct {
@@ -34,18 +65,57 @@ type RequestMessage struct {
 	Depth int
 }
 
+// ResponseMessage is the response of a crawled URL.
+// If the response is a redirect, Redirects contains every hop of the redirect chain.
+// If the response has been rendered, Rendered contains the HTML of the rendered DOM,
+// or RenderError is set if the rendering failed.
+// Timing is set if there is a response, and its Download time is set once the
+// response body has been read or closed. Transfer is also set if there is a response,
+// and its Size is updated as the response body is read.
 type ResponseMessage struct {
-	URL      string
-	Response *http.Response
-	Error    error
-	Depth    int
+	URL         string
+	Response    *http.Response
+	Error       error
+	Depth       int
+	Redirects   []models.RedirectHop
+	Rendered    []byte
+	RenderError error
+	Timing      *models.ResponseTiming
+	Transfer    *models.ResponseTransfer
+
+	// Responses of the redirect chain's hops after the first one, so their URLs
+	// don't have to be requested again.
+	RedirectResponses []*ResponseMessage
 }
 
-func New(client Client, urlStream <-chan *RequestMessage) *HttpCrawler {
+func New(client Client, urlStream <-chan *RequestMessage, options *Options) *HttpCrawler {
+	o := &Options{
+		Threads:     consumerThreads,
+		RandomDelay: randomDelay * time.Millisecond,
+	}
+
+	if options != nil {
+		if options.Threads > 0 {
+			o.Threads = options.Threads
+		}
+
+		if options.RandomDelay >= 0 {
+			o.RandomDelay = options.RandomDelay
+		}
+
+		o.RateLimiter = options.RateLimiter
+		o.Retries = max(options.Retries, 0)
+		o.RetryDelay = max(options.RetryDelay, 0)
+		o.Renderer = options.Renderer
+		o.Session = options.Session
+		o.FollowRedirect = options.FollowRedirect
+	}
+
 	return &HttpCrawler{
 		urlStream: urlStream,
 		rStream:   make(chan *ResponseMessage),
 		client:    client,
+		options:   o,
 	}
 }
 
@@ -57,9 +127,9 @@ func (c *HttpCrawler) Crawl(ctx context.Context) <-chan *ResponseMessage {
 		defer close(c.rStream)
 
 		wg := new(sync.WaitGroup)
-		wg.Add(consumerThreads)
+		wg.Add(c.options.Threads)
 
-		for i := 0; i < consumerThreads; i++ {
+		for i := 0; i < c.options.Threads; i++ {
 			go func() {
 				c.consumer(ctx)
 				wg.Done()
@@ -73,24 +143,271 @@ func (c *HttpCrawler) Crawl(ctx context.Context) <-chan *ResponseMessage {
 }
 
 // Consumer gets URLs from the urlStream until the context is cancelled.
-// It adds a random delay between client calls.
+// It adds a random delay between client calls and waits for the rate limiter if there is one.
+// If the context is cancelled while sending a response, the response is discarded.
 func (c *HttpCrawler) consumer(ctx context.Context) {
 	for {
 		select {
-		case requestMessage := <-c.urlStream:
+		case requestMessage, ok := <-c.urlStream:
+			if !ok {
+				return
+			}
+
 			// Add random delay to avoid overwhelming the servers with requests.
-			time.Sleep(time.Duration(rand.Intn(randomDelay)) * time.Millisecond)
+			if c.options.RandomDelay > 0 {
+				time.Sleep(time.Duration(rand.Int63n(int64(c.options.RandomDelay))))
+			}
 
 			rm := &ResponseMessage{
 				URL:   requestMessage.URL,
 				Depth: requestMessage.Depth,
 			}
 
-			rm.Response, rm.Error = c.client.Get(requestMessage.URL)
+			rm.Response, rm.Timing, rm.Error = c.fetch(ctx, requestMessage.URL)
+			if ctx.Err() != nil {
+				if rm.Response != nil {
+					rm.Response.Body.Close()
+				}
+
+				return
+			}
+
+			c.decode(rm)
+
+			if rm.Error == nil && isRedirect(rm.Response) {
+				rm.Redirects, rm.RedirectResponses = c.redirectChain(ctx, rm)
+			}
+
+			if c.options.Renderer != nil && rm.Error == nil && isRenderable(rm.Response) {
+				c.render(ctx, rm)
+			}
+
+			select {
+			case c.rStream <- rm:
+			case <-ctx.Done():
+				if rm.Response != nil {
+					rm.Response.Body.Close()
+				}
+
+				rm.CloseRedirectResponses()
 
-			c.rStream <- rm
+				return
+			}
 		case <-ctx.Done():
 			return
 		}
 	}
 }
+
+// Fetches the URL, retrying with an exponential backoff if the request fails
+// with a network error or a 429 or 5xx status code.
+// It returns the timing of the last request if there is a response.
+// It returns early with the context error if the context is done.
+func (c *HttpCrawler) fetch(ctx context.Context, u string) (*http.Response, *models.ResponseTiming, error) {
+	delay := c.options.RetryDelay
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx, u); err != nil {
+			return nil, nil, err
+		}
+
+		rt := newResponseTimer()
+		resp, err := c.client.GetContext(rt.withTrace(ctx), u)
+		if err == nil && resp != nil && c.options.RateLimiter != nil {
+			if parsed, err := url.Parse(u); err == nil {
+				c.options.RateLimiter.Update(parsed, resp)
+			}
+		}
+
+		if attempt >= c.options.Retries || (err == nil && resp == nil) || !retryable(resp, err) {
+			if err != nil || resp == nil {
+				return resp, nil, err
+			}
+
+			timing := rt.result()
+			resp.Body = rt.timeBody(resp.Body, timing)
+
+			return resp, timing, nil
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		}
+
+		delay *= 2
+	}
+}
+
+// Decodes the body of the message's response. If it can't be decoded the response
+// body is closed and the error is set in the message.
+func (c *HttpCrawler) decode(rm *ResponseMessage) {
+	if rm.Error != nil || rm.Response == nil {
+		return
+	}
+
+	rm.Transfer, rm.Error = decodeBody(rm.Response)
+	if rm.Error != nil {
+		rm.Response.Body.Close()
+		rm.Response = nil
+	}
+}
+
+// Follows the redirect chain of the message's URL, which returned a redirect response,
+// and returns all its hops. The chain ends with the first response that is not a redirect,
+// when a URL is repeated in a redirect loop, if a request fails or if maxRedirectHops is reached.
+// It also ends if the FollowRedirect option doesn't allow the hop's location, which is recorded
+// without being requested.
+// The responses of the following hops are also returned, one depth level deeper than the
+// previous hop. Their redirect hops are the ones of the chain starting at their URL.
+func (c *HttpCrawler) redirectChain(ctx context.Context, rm *ResponseMessage) ([]models.RedirectHop, []*ResponseMessage) {
+	hops := []models.RedirectHop{}
+	responses := []*ResponseMessage{}
+	seen := map[string]bool{}
+	current := rm
+
+	for {
+		hop := models.RedirectHop{
+			Hop:        len(hops),
+			URL:        current.URL,
+			StatusCode: current.Response.StatusCode,
+		}
+
+		if isRedirect(current.Response) {
+			hop.Location = location(current.URL, current.Response)
+		}
+
+		hops = append(hops, hop)
+		seen[current.URL] = true
+
+		if hop.Location == "" || seen[hop.Location] || hop.Hop >= maxRedirectHops {
+			break
+		}
+
+		if c.options.FollowRedirect != nil && !c.options.FollowRedirect(hop.Location) {
+			break
+		}
+
+		next := &ResponseMessage{URL: hop.Location, Depth: current.Depth + 1}
+		next.Response, next.Timing, next.Error = c.fetch(ctx, next.URL)
+		if ctx.Err() != nil {
+			if next.Response != nil {
+				next.Response.Body.Close()
+			}
+
+			break
+		}
+
+		c.decode(next)
+		responses = append(responses, next)
+
+		if next.Error != nil || next.Response == nil {
+			break
+		}
+
+		current = next
+	}
+
+	for i, r := range responses {
+		if isRedirect(r.Response) {
+			for _, h := range hops[i+1:] {
+				h.Hop -= i + 1
+				r.Redirects = append(r.Redirects, h)
+			}
+		}
+
+		if c.options.Renderer != nil && r.Error == nil && isRenderable(r.Response) {
+			c.render(ctx, r)
+		}
+	}
+
+	return hops, responses
+}
+
+// CloseRedirectResponses closes the bodies of the redirect hops responses that are not used.
+func (rm *ResponseMessage) CloseRedirectResponses() {
+	for _, r := range rm.RedirectResponses {
+		if r.Response != nil {
+			r.Response.Body.Close()
+		}
+	}
+
+	rm.RedirectResponses = nil
+}
+
+// Renders the response's URL with the Renderer once the rate limiter allows it.
+// The response body is read and replaced so it can still be parsed.
+func (c *HttpCrawler) render(ctx context.Context, rm *ResponseMessage) {
+	b, err := io.ReadAll(rm.Response.Body)
+	rm.Response.Body.Close()
+	rm.Response.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		rm.RenderError = err
+		return
+	}
+
+	// The browser requests the page again, so the rate limiter applies to it too.
+	if err := c.wait(ctx, rm.URL); err != nil {
+		rm.RenderError = err
+		return
+	}
+
+	rm.Rendered, rm.RenderError = c.options.Renderer.Render(ctx, rm.URL, c.options.Session)
+}
+
+// Returns true if the response is a successful HTML response that can be rendered.
+func isRenderable(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	return err == nil && mediaType == "text/html"
+}
+
+// Returns true if the response is a redirect with a Location header.
+func isRedirect(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+
+	return resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != ""
+}
+
+// Returns the absolute URL of the response's Location header, resolved against the URL u.
+// It returns an empty string if any of the URLs can't be parsed.
+func location(u string, resp *http.Response) string {
+	base, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+
+	l, err := base.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return ""
+	}
+
+	return l.String()
+}
+
+// Waits until the rate limiter allows a request to the URL.
+// It returns an error if the context is done while waiting.
+func (c *HttpCrawler) wait(ctx context.Context, u string) error {
+	if c.options.RateLimiter == nil {
+		return nil
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil
+	}
+
+	return c.options.RateLimiter.Wait(ctx, parsed)
+}
@@ -1,11 +1,19 @@
 package httpcrawler_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"github.com/andybalholm/brotli"
 )
 
 type MockClient struct{}
@@ -14,6 +22,10 @@ func (c *MockClient) Get(u string) (*http.Response, error) {
 	return nil, nil
 }
 
+func (c *MockClient) GetContext(ctx context.Context, u string) (*http.Response, error) {
+	return nil, nil
+}
+
 func (c *MockClient) Head(u string) (*http.Response, error) {
 	return nil, nil
 }
@@ -30,7 +42,7 @@ func TestHttpCrawler_Crawl(t *testing.T) {
 	testURL := "http://example.com"
 
 	client := &MockClient{}
-	crawler := httpcrawler.New(client, mockURLStream)
+	crawler := httpcrawler.New(client, mockURLStream, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -50,3 +62,363 @@ func TestHttpCrawler_Crawl(t *testing.T) {
 
 	cancel()
 }
+
+// RedirectClient returns a redirect to the location in the redirects map,
+// or a 200 response if the URL is not in the map.
+type RedirectClient struct {
+	MockClient
+	redirects map[string]string
+}
+
+func (c *RedirectClient) GetContext(ctx context.Context, u string) (*http.Response, error) {
+	l, ok := c.redirects[u]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusMovedPermanently,
+		Header:     http.Header{"Location": []string{l}},
+		Body:       http.NoBody,
+	}, nil
+}
+
+func TestRedirectChain(t *testing.T) {
+	table := []struct {
+		redirects map[string]string
+		want      []models.RedirectHop
+	}{
+		{
+			redirects: map[string]string{},
+			want:      nil,
+		},
+		{
+			redirects: map[string]string{
+				"http://example.com/a": "/b",
+				"http://example.com/b": "https://example.com/c",
+			},
+			want: []models.RedirectHop{
+				{Hop: 0, URL: "http://example.com/a", StatusCode: 301, Location: "http://example.com/b"},
+				{Hop: 1, URL: "http://example.com/b", StatusCode: 301, Location: "https://example.com/c"},
+				{Hop: 2, URL: "https://example.com/c", StatusCode: 200},
+			},
+		},
+		{
+			redirects: map[string]string{
+				"http://example.com/a": "/b",
+				"http://example.com/b": "/a",
+			},
+			want: []models.RedirectHop{
+				{Hop: 0, URL: "http://example.com/a", StatusCode: 301, Location: "http://example.com/b"},
+				{Hop: 1, URL: "http://example.com/b", StatusCode: 301, Location: "http://example.com/a"},
+			},
+		},
+	}
+
+	for _, v := range table {
+		urlStream := make(chan *httpcrawler.RequestMessage, 1)
+		urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com/a"}
+
+		crawler := httpcrawler.New(&RedirectClient{redirects: v.redirects}, urlStream, &httpcrawler.Options{Threads: 1})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := <-crawler.Crawl(ctx)
+		cancel()
+		close(urlStream)
+
+		if len(result.Redirects) != len(v.want) {
+			t.Fatalf("redirect hops %d != %d", len(result.Redirects), len(v.want))
+		}
+
+		for i, h := range v.want {
+			if result.Redirects[i] != h {
+				t.Errorf("hop %d: %+v != %+v", i, result.Redirects[i], h)
+			}
+		}
+	}
+}
+
+func TestRedirectChainFollowRedirect(t *testing.T) {
+	client := &RedirectClient{redirects: map[string]string{
+		"http://example.com/a":       "/b",
+		"http://example.com/b":       "https://other.example.net/",
+		"https://other.example.net/": "https://other.example.net/next",
+	}}
+
+	urlStream := make(chan *httpcrawler.RequestMessage, 1)
+	urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com/a"}
+
+	crawler := httpcrawler.New(client, urlStream, &httpcrawler.Options{
+		Threads: 1,
+		FollowRedirect: func(u string) bool {
+			return strings.HasPrefix(u, "http://example.com/")
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := <-crawler.Crawl(ctx)
+	cancel()
+	close(urlStream)
+
+	want := []models.RedirectHop{
+		{Hop: 0, URL: "http://example.com/a", StatusCode: 301, Location: "http://example.com/b"},
+		{Hop: 1, URL: "http://example.com/b", StatusCode: 301, Location: "https://other.example.net/"},
+	}
+
+	if len(result.Redirects) != len(want) {
+		t.Fatalf("redirect hops %d != %d", len(result.Redirects), len(want))
+	}
+
+	for i, h := range want {
+		if result.Redirects[i] != h {
+			t.Errorf("hop %d: %+v != %+v", i, result.Redirects[i], h)
+		}
+	}
+}
+
+func TestRedirectChainResponses(t *testing.T) {
+	client := &RedirectClient{redirects: map[string]string{
+		"http://example.com/a": "/b",
+		"http://example.com/b": "/c",
+	}}
+
+	urlStream := make(chan *httpcrawler.RequestMessage, 1)
+	urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com/a", Depth: 1}
+
+	crawler := httpcrawler.New(client, urlStream, &httpcrawler.Options{Threads: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := <-crawler.Crawl(ctx)
+	cancel()
+	close(urlStream)
+
+	want := []struct {
+		url        string
+		depth      int
+		statusCode int
+		redirects  []models.RedirectHop
+	}{
+		{
+			"http://example.com/b",
+			2,
+			http.StatusMovedPermanently,
+			[]models.RedirectHop{
+				{Hop: 0, URL: "http://example.com/b", StatusCode: 301, Location: "http://example.com/c"},
+				{Hop: 1, URL: "http://example.com/c", StatusCode: 200},
+			},
+		},
+		{"http://example.com/c", 3, http.StatusOK, nil},
+	}
+
+	if len(result.RedirectResponses) != len(want) {
+		t.Fatalf("redirect responses %d != %d", len(result.RedirectResponses), len(want))
+	}
+
+	for i, w := range want {
+		rm := result.RedirectResponses[i]
+		if rm.URL != w.url || rm.Depth != w.depth || rm.Response.StatusCode != w.statusCode {
+			t.Errorf("response %d: %s %d %d != %s %d %d", i, rm.URL, rm.Depth, rm.Response.StatusCode, w.url, w.depth, w.statusCode)
+		}
+
+		if len(rm.Redirects) != len(w.redirects) {
+			t.Fatalf("response %d redirect hops %d != %d", i, len(rm.Redirects), len(w.redirects))
+		}
+
+		for j, h := range w.redirects {
+			if rm.Redirects[j] != h {
+				t.Errorf("response %d hop %d: %+v != %+v", i, j, rm.Redirects[j], h)
+			}
+		}
+	}
+}
+
+// FakeRenderer returns the same rendered HTML for any URL.
+type FakeRenderer struct {
+	html string
+}
+
+func (r *FakeRenderer) Render(ctx context.Context, u string, session *httpcrawler.ClientOptions) ([]byte, error) {
+	return []byte(r.html), nil
+}
+
+// HTMLClient returns a 200 response with the given content type and body.
+type HTMLClient struct {
+	MockClient
+	contentType string
+	body        string
+}
+
+func (c *HTMLClient) GetContext(ctx context.Context, u string) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{c.contentType}},
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+	}, nil
+}
+
+func TestHttpCrawlerRender(t *testing.T) {
+	table := []struct {
+		contentType string
+		rendered    string
+	}{
+		{"text/html; charset=utf-8", "<html><body>rendered</body></html>"},
+		{"application/pdf", ""},
+	}
+
+	for _, v := range table {
+		urlStream := make(chan *httpcrawler.RequestMessage, 1)
+		urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com"}
+
+		client := &HTMLClient{contentType: v.contentType, body: "raw"}
+		crawler := httpcrawler.New(client, urlStream, &httpcrawler.Options{
+			Threads:  1,
+			Renderer: &FakeRenderer{html: "<html><body>rendered</body></html>"},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := <-crawler.Crawl(ctx)
+		cancel()
+		close(urlStream)
+
+		if string(result.Rendered) != v.rendered {
+			t.Errorf("rendered %q != %q", result.Rendered, v.rendered)
+		}
+
+		body, _ := io.ReadAll(result.Response.Body)
+		if string(body) != "raw" {
+			t.Errorf("body %q != raw", body)
+		}
+	}
+}
+
+// SlowClient returns a 200 response after waiting for the delay.
+type SlowClient struct {
+	MockClient
+	delay time.Duration
+}
+
+func (c *SlowClient) GetContext(ctx context.Context, u string) (*http.Response, error) {
+	time.Sleep(c.delay)
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("body"))}, nil
+}
+
+func TestHttpCrawlerTiming(t *testing.T) {
+	urlStream := make(chan *httpcrawler.RequestMessage, 1)
+	urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com"}
+
+	client := &SlowClient{delay: 20 * time.Millisecond}
+	crawler := httpcrawler.New(client, urlStream, &httpcrawler.Options{Threads: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := <-crawler.Crawl(ctx)
+	cancel()
+	close(urlStream)
+
+	if result.Timing == nil {
+		t.Fatal("timing is nil")
+	}
+
+	if result.Timing.Download != 0 {
+		t.Errorf("download %d should not be set before reading the body", result.Timing.Download)
+	}
+
+	io.ReadAll(result.Response.Body)
+	result.Response.Body.Close()
+
+	if result.Timing.Download < 20 {
+		t.Errorf("download %d < 20", result.Timing.Download)
+	}
+}
+
+// EncodedClient returns a 200 response with the body and its Content-Encoding header.
+type EncodedClient struct {
+	MockClient
+	encoding string
+	body     []byte
+}
+
+func (c *EncodedClient) GetContext(ctx context.Context, u string) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/2.0",
+		Header:     http.Header{"Content-Encoding": []string{c.encoding}},
+		Body:       io.NopCloser(bytes.NewReader(c.body)),
+	}, nil
+}
+
+func TestHttpCrawlerTransfer(t *testing.T) {
+	want := strings.Repeat("<p>compressed</p>", 100)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte(want))
+	gw.Close()
+
+	var br bytes.Buffer
+	bw := brotli.NewWriter(&br)
+	bw.Write([]byte(want))
+	bw.Close()
+
+	table := []struct {
+		encoding string
+		body     []byte
+	}{
+		{"gzip", gz.Bytes()},
+		{"br", br.Bytes()},
+	}
+
+	for _, v := range table {
+		urlStream := make(chan *httpcrawler.RequestMessage, 1)
+		urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com"}
+
+		client := &EncodedClient{encoding: v.encoding, body: v.body}
+		crawler := httpcrawler.New(client, urlStream, &httpcrawler.Options{Threads: 1})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := <-crawler.Crawl(ctx)
+		cancel()
+		close(urlStream)
+
+		if result.Error != nil {
+			t.Fatalf("%s error %v", v.encoding, result.Error)
+		}
+
+		body, _ := io.ReadAll(result.Response.Body)
+		result.Response.Body.Close()
+
+		if string(body) != want {
+			t.Errorf("%s body was not decoded", v.encoding)
+		}
+
+		if result.Response.Header.Get("Content-Encoding") != "" {
+			t.Errorf("%s Content-Encoding header should be removed", v.encoding)
+		}
+
+		wantTransfer := models.ResponseTransfer{Protocol: "HTTP/2.0", ContentEncoding: v.encoding, Size: len(v.body)}
+		if *result.Transfer != wantTransfer {
+			t.Errorf("transfer %+v != %+v", *result.Transfer, wantTransfer)
+		}
+	}
+}
+
+func TestHttpCrawlerUnsupportedEncoding(t *testing.T) {
+	urlStream := make(chan *httpcrawler.RequestMessage, 1)
+	urlStream <- &httpcrawler.RequestMessage{URL: "http://example.com"}
+
+	client := &EncodedClient{encoding: "zstd", body: []byte("compressed")}
+	crawler := httpcrawler.New(client, urlStream, &httpcrawler.Options{Threads: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := <-crawler.Crawl(ctx)
+	cancel()
+	close(urlStream)
+
+	if result.Response != nil || httpcrawler.FetchError(result.Error) != httpcrawler.FetchErrorEncoding {
+		t.Errorf("response with unsupported encoding: error %v", result.Error)
+	}
+
+	if result.Transfer == nil || result.Transfer.ContentEncoding != "zstd" {
+		t.Errorf("transfer %+v should record the content encoding", result.Transfer)
+	}
+}
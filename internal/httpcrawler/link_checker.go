@@ -0,0 +1,151 @@
+package httpcrawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+const (
+	// Min time between the requests sent to each external host.
+	linkCheckDelay = time.Second
+
+	// Max number of bytes read from the body of the GET responses before closing them.
+	linkCheckBodySize = 4096
+)
+
+// LinkChecker requests a list of external URLs to find their status. Each URL is requested once
+// with a HEAD request, and again with a GET request if the HEAD request fails or its status code
+// is an error, as some servers don't implement HEAD requests. Requests to the same host are
+// rate-limited and redirects are not followed.
+type LinkChecker struct {
+	client  Client
+	limiter *RateLimiter
+	threads int
+}
+
+// NewLinkChecker returns a LinkChecker that sends the requests with the client using
+// the specified number of threads.
+func NewLinkChecker(client Client, threads int) *LinkChecker {
+	return &LinkChecker{
+		client: client,
+		limiter: NewRateLimiter(func(*url.URL) time.Duration {
+			return linkCheckDelay
+		}),
+		threads: max(threads, 1),
+	}
+}
+
+// Check requests the urls and sends their status through the returned channel,
+// which is closed once all the URLs have been checked or the context is done.
+func (lc *LinkChecker) Check(ctx context.Context, urls []string) <-chan *models.ExternalLinkStatus {
+	uStream := make(chan string)
+	sStream := make(chan *models.ExternalLinkStatus)
+
+	go func() {
+		defer close(uStream)
+
+		for _, u := range urls {
+			select {
+			case uStream <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < lc.threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for u := range uStream {
+				status, ok := lc.check(ctx, u)
+				if !ok {
+					continue
+				}
+
+				select {
+				case sStream <- status:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(sStream)
+	}()
+
+	return sStream
+}
+
+// Returns the status of the URL u. It returns false if the URL is not valid
+// or the context is done before the URL is checked.
+func (lc *LinkChecker) check(ctx context.Context, u string) (*models.ExternalLinkStatus, bool) {
+	parsedURL, err := url.Parse(u)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return nil, false
+	}
+
+	status := &models.ExternalLinkStatus{URL: u}
+
+	resp, err := lc.request(ctx, http.MethodHead, parsedURL)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		if ctx.Err() != nil {
+			return nil, false
+		}
+
+		resp, err = lc.request(ctx, http.MethodGet, parsedURL)
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, false
+		}
+
+		status.FetchError = FetchError(err)
+
+		return status, true
+	}
+
+	status.StatusCode = resp.StatusCode
+	if isRedirect(resp) {
+		status.RedirectURL = location(u, resp)
+	}
+
+	return status, true
+}
+
+// Waits for the rate limiter and sends a request with the method to the URL.
+// The response body is discarded and closed.
+func (lc *LinkChecker) request(ctx context.Context, method string, u *url.URL) (*http.Response, error) {
+	if err := lc.limiter.Wait(ctx, u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := lc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, linkCheckBodySize))
+	resp.Body.Close()
+
+	lc.limiter.Update(u, resp)
+
+	return resp, nil
+}
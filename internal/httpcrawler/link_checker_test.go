@@ -0,0 +1,85 @@
+package httpcrawler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// LinkClient responds to the requests with the status code in the statuses map for the
+// request's method and URL. HEAD requests to URLs not in the map fail with an error.
+type LinkClient struct {
+	MockClient
+	statuses map[string]int
+}
+
+func (c *LinkClient) Do(req *http.Request) (*http.Response, error) {
+	status, ok := c.statuses[req.Method+" "+req.URL.String()]
+	if !ok {
+		return nil, errors.New("connection failed")
+	}
+
+	resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: http.NoBody}
+	if status == http.StatusMovedPermanently {
+		resp.Header.Set("Location", "/new")
+	}
+
+	return resp, nil
+}
+
+func TestLinkChecker(t *testing.T) {
+	client := &LinkClient{
+		statuses: map[string]int{
+			"HEAD https://a.example.com/": http.StatusOK,
+			"HEAD https://b.example.com/": http.StatusMethodNotAllowed,
+			"GET https://b.example.com/":  http.StatusOK,
+			"HEAD https://c.example.com/": http.StatusNotFound,
+			"GET https://c.example.com/":  http.StatusNotFound,
+			"HEAD https://d.example.com/": http.StatusMovedPermanently,
+		},
+	}
+
+	urls := []string{
+		"https://a.example.com/",
+		"https://b.example.com/",
+		"https://c.example.com/",
+		"https://d.example.com/",
+		"https://e.example.com/",
+		"mailto:info@example.com",
+	}
+
+	expected := map[string]models.ExternalLinkStatus{
+		"https://a.example.com/": {StatusCode: http.StatusOK},
+		"https://b.example.com/": {StatusCode: http.StatusOK},
+		"https://c.example.com/": {StatusCode: http.StatusNotFound},
+		"https://d.example.com/": {StatusCode: http.StatusMovedPermanently, RedirectURL: "https://d.example.com/new"},
+		"https://e.example.com/": {FetchError: httpcrawler.FetchErrorNetwork},
+	}
+
+	checker := httpcrawler.NewLinkChecker(client, 2)
+
+	statuses := make(map[string]*models.ExternalLinkStatus)
+	for s := range checker.Check(context.Background(), urls) {
+		statuses[s.URL] = s
+	}
+
+	if len(statuses) != len(expected) {
+		t.Errorf("Expected %d statuses, got %d", len(expected), len(statuses))
+	}
+
+	for u, e := range expected {
+		s, ok := statuses[u]
+		if !ok {
+			t.Errorf("Missing status for %s", u)
+			continue
+		}
+
+		if s.StatusCode != e.StatusCode || s.RedirectURL != e.RedirectURL || s.FetchError != e.FetchError {
+			t.Errorf("%s: expected %+v, got %+v", u, e, *s)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package httpcrawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Initial backoff after a 429 or 503 response without a valid Retry-After header.
+	minBackoff = time.Second
+
+	// Max time requests to a host are paused after a 429 or 503 response.
+	maxBackoff = 5 * time.Minute
+)
+
+// RateLimiter limits the rate of the requests sent to each host.
+// Requests to the same host are spaced by the host's delay, which is usually the robots.txt
+// Crawl-delay, plus an adaptive backoff that grows when the host responds with a 429 or 503
+// status code and shrinks when it responds successfully.
+type RateLimiter struct {
+	delay func(*url.URL) time.Duration
+	hosts map[string]*hostLimit
+	lock  *sync.Mutex
+}
+
+type hostLimit struct {
+	next    time.Time     // Time when the next request to the host is allowed
+	backoff time.Duration // Current backoff added to the host's delay
+}
+
+// NewRateLimiter returns a RateLimiter. The delay function returns the min time
+// between requests to an URL's host. If delay is nil there is no min time.
+func NewRateLimiter(delay func(*url.URL) time.Duration) *RateLimiter {
+	return &RateLimiter{
+		delay: delay,
+		hosts: make(map[string]*hostLimit),
+		lock:  &sync.Mutex{},
+	}
+}
+
+// Wait blocks until a request to the URL's host is allowed, reserving the host's next slot.
+// It returns an error if the context is done before the request is allowed.
+func (r *RateLimiter) Wait(ctx context.Context, u *url.URL) error {
+	var delay time.Duration
+	if r.delay != nil {
+		delay = r.delay(u)
+	}
+
+	r.lock.Lock()
+	h := r.host(u.Host)
+	now := time.Now()
+	start := h.next
+	if start.Before(now) {
+		start = now
+	}
+	h.next = start.Add(delay + h.backoff)
+	r.lock.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Update adapts the rate of the requests to the URL's host depending on the response.
+// On 429 and 503 responses, requests to the host are paused for the time in the Retry-After
+// header or, if it is not set, for an exponential backoff.
+func (r *RateLimiter) Update(u *url.URL, resp *http.Response) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	h := r.host(u.Host)
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		h.backoff /= 2
+		if h.backoff < minBackoff {
+			h.backoff = 0
+		}
+
+		return
+	}
+
+	h.backoff = min(max(h.backoff*2, minBackoff), maxBackoff)
+
+	pause := h.backoff
+	if retryAfter := RetryAfter(resp.Header); retryAfter > 0 {
+		pause = min(retryAfter, maxBackoff)
+	}
+
+	if next := time.Now().Add(pause); next.After(h.next) {
+		h.next = next
+	}
+}
+
+// Returns the host's limit, creating it if it doesn't exist.
+// The lock must be held by the caller.
+func (r *RateLimiter) host(host string) *hostLimit {
+	h, ok := r.hosts[host]
+	if !ok {
+		h = &hostLimit{}
+		r.hosts[host] = h
+	}
+
+	return h
+}
+
+// RetryAfter returns the duration in the Retry-After header, which can be
+// specified in seconds or as an HTTP date. It returns 0 if the header is not valid.
+func RetryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,78 @@
+package httpcrawler_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+)
+
+func TestRateLimiterDelay(t *testing.T) {
+	delay := 50 * time.Millisecond
+	limiter := httpcrawler.NewRateLimiter(func(u *url.URL) time.Duration {
+		return delay
+	})
+
+	u, _ := url.Parse("https://example.com/")
+	other, _ := url.Parse("https://example.org/")
+
+	start := time.Now()
+	limiter.Wait(context.Background(), u)
+	limiter.Wait(context.Background(), other)
+	if time.Since(start) >= delay {
+		t.Errorf("First requests to each host should not wait")
+	}
+
+	limiter.Wait(context.Background(), u)
+	if time.Since(start) < delay {
+		t.Errorf("Second request to the host should wait for the delay")
+	}
+}
+
+func TestRateLimiterRetryAfter(t *testing.T) {
+	limiter := httpcrawler.NewRateLimiter(nil)
+	u, _ := url.Parse("https://example.com/")
+
+	limiter.Update(u, &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"1"}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, u); err == nil {
+		t.Errorf("Request should wait for the Retry-After time")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	table := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"120", 120 * time.Second},
+		{"-5", 0},
+		{"invalid", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+
+	for _, v := range table {
+		h := http.Header{}
+		h.Set("Retry-After", v.header)
+
+		if d := httpcrawler.RetryAfter(h); d != v.expected {
+			t.Errorf("RetryAfter %q: %v != %v", v.header, d, v.expected)
+		}
+	}
+
+	h := http.Header{}
+	h.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if d := httpcrawler.RetryAfter(h); d <= 0 || d > time.Hour {
+		t.Errorf("RetryAfter date: %v", d)
+	}
+}
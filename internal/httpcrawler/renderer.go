@@ -0,0 +1,401 @@
+package httpcrawler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Default time to wait for a page to be rendered.
+	renderTimeout = 30 * time.Second
+
+	// Time to wait after the page load event so scripts can finish updating the DOM.
+	renderSettle = 500 * time.Millisecond
+)
+
+// Resource types of the DevTools protocol that are not requested when rendering a page,
+// as they don't change its DOM.
+var blockedResources = map[string]bool{
+	"Image":      true,
+	"Media":      true,
+	"Font":       true,
+	"Stylesheet": true,
+}
+
+// Renderer returns the HTML of the URL's DOM once its JavaScript has been executed.
+// If session is not nil, its BasicAuth credentials, custom Headers and the cookies stored
+// in its CookieJar are sent in the requests to the session's AuthDomains.
+type Renderer interface {
+	Render(ctx context.Context, u string, session *ClientOptions) ([]byte, error)
+}
+
+// ChromeRenderer renders pages with a headless Chromium browser using the DevTools protocol.
+// The browser must be started with remote debugging enabled, for instance:
+//
+//	chromium --headless --remote-debugging-port=9222
+//
+// Each page is rendered in a new tab, so it can render several pages concurrently.
+type ChromeRenderer struct {
+	endpoint  string
+	userAgent string
+	timeout   time.Duration
+	client    *http.Client
+}
+
+// A message of the DevTools protocol. Commands have an Id and a Method, their responses
+// have the same Id and a Result or an Error. Events only have a Method and its Params.
+type cdpMessage struct {
+	Id     int             `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// A DevTools protocol session with a browser tab. If headers is not nil, the requests
+// to the auth domains paused by the Fetch domain are continued adding the headers.
+type cdpSession struct {
+	conn    *websocket.Conn
+	lastId  int
+	loaded  bool
+	auth    *ClientOptions
+	headers http.Header
+}
+
+// NewChromeRenderer returns a ChromeRenderer using the browser's DevTools HTTP endpoint,
+// such as http://127.0.0.1:9222. Pages taking longer than timeout to render return an error.
+func NewChromeRenderer(endpoint, userAgent string, timeout time.Duration) *ChromeRenderer {
+	if timeout <= 0 {
+		timeout = renderTimeout
+	}
+
+	return &ChromeRenderer{
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		userAgent: userAgent,
+		timeout:   timeout,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Render opens the URL in a new browser tab, waits for the page to load and
+// returns the HTML of the rendered DOM. The tab is closed afterwards.
+func (r *ChromeRenderer) Render(ctx context.Context, u string, session *ClientOptions) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	target, err := r.newTarget(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeTarget(target.Id)
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, target.WebSocketDebuggerURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	conn.SetReadDeadline(deadline)
+	conn.SetWriteDeadline(deadline)
+
+	// Close the connection if the context is cancelled, so any pending read returns.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	s := &cdpSession{conn: conn}
+
+	if _, err := s.call("Page.enable", nil); err != nil {
+		return nil, err
+	}
+
+	if r.userAgent != "" {
+		if _, err := s.call("Network.setUserAgentOverride", map[string]string{"userAgent": r.userAgent}); err != nil {
+			return nil, err
+		}
+	}
+
+	if session != nil {
+		if err := s.setSession(session); err != nil {
+			return nil, err
+		}
+	}
+
+	// The requests are intercepted to block the resources that are not needed to render
+	// the DOM and to add the session headers.
+	_, err = s.call("Fetch.enable", map[string]any{
+		"patterns": []map[string]string{{"urlPattern": "*", "requestStage": "Request"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.call("Page.navigate", map[string]string{"url": u})
+	if err != nil {
+		return nil, err
+	}
+
+	var navigation struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := json.Unmarshal(result, &navigation); err == nil && navigation.ErrorText != "" {
+		return nil, fmt.Errorf("render %s: %s", u, navigation.ErrorText)
+	}
+
+	if err := s.waitLoad(); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-time.After(renderSettle):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	result, err = s.call("Runtime.evaluate", map[string]any{
+		"expression":    "document.documentElement.outerHTML",
+		"returnByValue": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluation struct {
+		Result struct {
+			Value string `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(result, &evaluation); err != nil {
+		return nil, err
+	}
+
+	return []byte("<!DOCTYPE html>" + evaluation.Result.Value), nil
+}
+
+// A browser tab returned by the DevTools HTTP endpoint.
+type cdpTarget struct {
+	Id                   string `json:"id"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+// Opens a new blank tab in the browser.
+func (r *ChromeRenderer) newTarget(ctx context.Context) (*cdpTarget, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.endpoint+"/json/new?about:blank", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("new browser tab: status code %d", resp.StatusCode)
+	}
+
+	target := &cdpTarget{}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return nil, err
+	}
+
+	if target.WebSocketDebuggerURL == "" {
+		return nil, errors.New("new browser tab: missing webSocketDebuggerUrl")
+	}
+
+	return target, nil
+}
+
+// Closes the browser tab with the target id.
+func (r *ChromeRenderer) closeTarget(id string) {
+	resp, err := r.client.Get(r.endpoint + "/json/close/" + url.PathEscape(id))
+	if err != nil {
+		return
+	}
+
+	resp.Body.Close()
+}
+
+// Sends the session's cookies to the browser and keeps the session's BasicAuth credentials
+// and custom headers so they are added to the requests to the auth domains.
+func (s *cdpSession) setSession(session *ClientOptions) error {
+	s.auth = session
+
+	if session.CookieJar != nil {
+		cookies := []map[string]string{}
+		for _, domain := range session.AuthDomains {
+			for _, scheme := range []string{"http", "https"} {
+				u := &url.URL{Scheme: scheme, Host: domain, Path: "/"}
+				for _, c := range session.CookieJar.Cookies(u) {
+					cookies = append(cookies, map[string]string{"name": c.Name, "value": c.Value, "url": u.String()})
+				}
+			}
+		}
+
+		if len(cookies) > 0 {
+			if _, err := s.call("Network.setCookies", map[string]any{"cookies": cookies}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !session.BasicAuth && len(session.Headers) == 0 {
+		return nil
+	}
+
+	s.headers = session.Headers.Clone()
+	if s.headers == nil {
+		s.headers = http.Header{}
+	}
+
+	if session.BasicAuth {
+		credentials := base64.StdEncoding.EncodeToString([]byte(session.AuthUser + ":" + session.AuthPass))
+		s.headers.Set("Authorization", "Basic "+credentials)
+	}
+
+	return nil
+}
+
+// Continues a request paused by the Fetch domain, or fails it if its resource type is blocked.
+// The session headers are added if the request is made to one of the auth domains.
+func (s *cdpSession) continueRequest(params json.RawMessage) error {
+	var paused struct {
+		RequestId    string `json:"requestId"`
+		ResourceType string `json:"resourceType"`
+		Request      struct {
+			URL     string            `json:"url"`
+			Headers map[string]string `json:"headers"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(params, &paused); err != nil {
+		return err
+	}
+
+	if blockedResources[paused.ResourceType] {
+		_, err := s.send("Fetch.failRequest", map[string]string{
+			"requestId":   paused.RequestId,
+			"errorReason": "BlockedByClient",
+		})
+
+		return err
+	}
+
+	continued := map[string]any{"requestId": paused.RequestId}
+
+	parsed, err := url.Parse(paused.Request.URL)
+	if err == nil && s.headers != nil && s.auth.isAuthDomain(parsed.Host) {
+		h := http.Header{}
+		for name, value := range paused.Request.Headers {
+			h.Set(name, value)
+		}
+
+		for name, values := range s.headers {
+			if name == "Authorization" {
+				h.Set(name, values[0])
+				continue
+			}
+
+			for _, v := range values {
+				h.Add(name, v)
+			}
+		}
+
+		headers := []map[string]string{}
+		for name, values := range h {
+			for _, v := range values {
+				headers = append(headers, map[string]string{"name": name, "value": v})
+			}
+		}
+
+		continued["headers"] = headers
+	}
+
+	_, err = s.send("Fetch.continueRequest", continued)
+
+	return err
+}
+
+// Sends a command without waiting for its response and returns the command id.
+func (s *cdpSession) send(method string, params any) (int, error) {
+	s.lastId++
+	m := &cdpMessage{Id: s.lastId, Method: method}
+
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return 0, err
+		}
+
+		m.Params = b
+	}
+
+	return m.Id, s.conn.WriteJSON(m)
+}
+
+// Sends a command and returns its result, reading the messages received until its response.
+func (s *cdpSession) call(method string, params any) (json.RawMessage, error) {
+	id, err := s.send(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		m, err := s.read()
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Id != id {
+			continue
+		}
+
+		if m.Error != nil {
+			return nil, fmt.Errorf("%s: %s", method, m.Error.Message)
+		}
+
+		return m.Result, nil
+	}
+}
+
+// Waits until the page load event has been received.
+func (s *cdpSession) waitLoad() error {
+	for !s.loaded {
+		if _, err := s.read(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reads the next message, keeping track of the page load event and continuing
+// the requests paused by the Fetch domain.
+func (s *cdpSession) read() (*cdpMessage, error) {
+	m := &cdpMessage{}
+	if err := s.conn.ReadJSON(m); err != nil {
+		return nil, err
+	}
+
+	switch m.Method {
+	case "Page.loadEventFired":
+		s.loaded = true
+	case "Fetch.requestPaused":
+		if err := s.continueRequest(m.Params); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
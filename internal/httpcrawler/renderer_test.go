@@ -0,0 +1,167 @@
+package httpcrawler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+
+	"github.com/gorilla/websocket"
+)
+
+// Returns a fake DevTools server that renders every page with the given outer HTML.
+// If the Fetch domain is enabled, it pauses the page request, a request to a script
+// in another domain and a request to an image. The received commands are sent to the commands channel if it is not nil.
+func newDevToolsServer(t *testing.T, outerHTML string, commands chan<- map[string]any) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	mux.HandleFunc("/json/new", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"id":                   "tab",
+			"webSocketDebuggerUrl": "ws" + strings.TrimPrefix(server.URL, "http") + "/devtools/page/tab",
+		})
+	})
+
+	mux.HandleFunc("/json/close/", func(w http.ResponseWriter, r *http.Request) {})
+
+	mux.HandleFunc("/devtools/page/tab", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		fetch := false
+		for {
+			m := map[string]any{}
+			if err := conn.ReadJSON(&m); err != nil {
+				return
+			}
+
+			if commands != nil {
+				commands <- m
+			}
+
+			result := map[string]any{}
+			switch m["method"] {
+			case "Fetch.enable":
+				fetch = true
+			case "Page.navigate":
+				conn.WriteJSON(map[string]any{"method": "Page.frameStartedLoading"})
+				if fetch {
+					page := m["params"].(map[string]any)["url"].(string)
+					requests := [][2]string{{page, "Document"}, {"https://cdn.example.net/app.js", "Script"}, {page + "logo.png", "Image"}}
+					for i, r := range requests {
+						conn.WriteJSON(map[string]any{"method": "Fetch.requestPaused", "params": map[string]any{
+							"requestId":    strconv.Itoa(i),
+							"resourceType": r[1],
+							"request":      map[string]any{"url": r[0], "headers": map[string]string{"Accept": "*/*"}},
+						}})
+					}
+				}
+			case "Runtime.evaluate":
+				result = map[string]any{"result": map[string]any{"type": "string", "value": outerHTML}}
+			}
+
+			conn.WriteJSON(map[string]any{"id": m["id"], "result": result})
+
+			if m["method"] == "Page.navigate" {
+				conn.WriteJSON(map[string]any{"method": "Page.loadEventFired"})
+			}
+		}
+	})
+
+	return server
+}
+
+func TestChromeRenderer(t *testing.T) {
+	outerHTML := "<html><head><title>Rendered</title></head><body></body></html>"
+	server := newDevToolsServer(t, outerHTML, nil)
+	defer server.Close()
+
+	r := httpcrawler.NewChromeRenderer(server.URL, "testing", 5*time.Second)
+
+	b, err := r.Render(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	if string(b) != "<!DOCTYPE html>"+outerHTML {
+		t.Errorf("rendered %q", b)
+	}
+}
+
+func TestChromeRendererSession(t *testing.T) {
+	commands := make(chan map[string]any, 100)
+	server := newDevToolsServer(t, "<html></html>", commands)
+	defer server.Close()
+
+	jar, _ := cookiejar.New(nil)
+	jar.SetCookies(&url.URL{Scheme: "https", Host: "example.com", Path: "/"}, []*http.Cookie{{Name: "session", Value: "abc"}})
+
+	session := &httpcrawler.ClientOptions{
+		BasicAuth:   true,
+		AuthDomains: []string{"example.com"},
+		AuthUser:    "user",
+		AuthPass:    "pass",
+		Headers:     http.Header{"X-Custom": []string{"value"}},
+		CookieJar:   jar,
+	}
+
+	r := httpcrawler.NewChromeRenderer(server.URL, "testing", 5*time.Second)
+	if _, err := r.Render(context.Background(), "https://example.com/", session); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	close(commands)
+
+	cookies := false
+	continued := map[string]string{}
+	failed := map[string]bool{}
+	for m := range commands {
+		params, _ := m["params"].(map[string]any)
+		switch m["method"] {
+		case "Network.setCookies":
+			b, _ := json.Marshal(params["cookies"])
+			cookies = strings.Contains(string(b), `"name":"session"`)
+		case "Fetch.continueRequest":
+			b, _ := json.Marshal(params["headers"])
+			continued[params["requestId"].(string)] = string(b)
+		case "Fetch.failRequest":
+			failed[params["requestId"].(string)] = true
+		}
+	}
+
+	if !cookies {
+		t.Error("the session cookie was not sent to the browser")
+	}
+
+	page := continued["0"]
+	if !strings.Contains(page, `"name":"Authorization","value":"Basic dXNlcjpwYXNz"`) || !strings.Contains(page, `"name":"X-Custom","value":"value"`) {
+		t.Errorf("page request headers %s", page)
+	}
+
+	if script, ok := continued["1"]; !ok || script != "null" {
+		t.Errorf("other domain request headers %q", script)
+	}
+
+	if _, ok := continued["2"]; ok || !failed["2"] {
+		t.Error("the image request should be blocked")
+	}
+}
@@ -0,0 +1,123 @@
+package httpcrawler
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// responseTimer records the timing of a request using an httptrace.ClientTrace.
+// The trace hooks may be called from the transport's goroutines, so the times are
+// guarded by a mutex.
+type responseTimer struct {
+	lock         sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	timing       models.ResponseTiming
+}
+
+// Returns a new responseTimer that starts counting the time now.
+func newResponseTimer() *responseTimer {
+	return &responseTimer{start: time.Now()}
+}
+
+// Returns a copy of the context ctx with the responseTimer's trace.
+func (t *responseTimer) withTrace(ctx context.Context) context.Context {
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.timing.DNS = milliseconds(time.Since(t.dnsStart))
+		},
+		ConnectStart: func(string, string) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.timing.Connect = milliseconds(time.Since(t.connectStart))
+		},
+		TLSHandshakeStart: func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.timing.TLS = milliseconds(time.Since(t.tlsStart))
+		},
+		GotFirstResponseByte: func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.timing.TTFB = milliseconds(time.Since(t.start))
+		},
+	}
+
+	return httptrace.WithClientTrace(ctx, trace)
+}
+
+// Returns the timing recorded so far. The Download time is set when
+// the body returned by timeBody has been read.
+func (t *responseTimer) result() *models.ResponseTiming {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	timing := t.timing
+
+	return &timing
+}
+
+// Wraps the response body so the Download time of timing is set once the body
+// has been read until the end or closed, whichever happens first.
+func (t *responseTimer) timeBody(body io.ReadCloser, timing *models.ResponseTiming) io.ReadCloser {
+	return &timedBody{ReadCloser: body, start: t.start, timing: timing}
+}
+
+// timedBody is a response body that sets the Download time of the response once it has been read.
+type timedBody struct {
+	io.ReadCloser
+	start  time.Time
+	timing *models.ResponseTiming
+	once   sync.Once
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.done()
+	}
+
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	b.done()
+
+	return b.ReadCloser.Close()
+}
+
+func (b *timedBody) done() {
+	b.once.Do(func() {
+		b.timing.Download = milliseconds(time.Since(b.start))
+	})
+}
+
+// Returns the duration d in milliseconds.
+func milliseconds(d time.Duration) int {
+	return int(d.Milliseconds())
+}
@@ -0,0 +1,96 @@
+package httpcrawler
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	// Accept-Encoding header sent in the requests of the crawled URLs.
+	// Only the encodings that can be decoded are accepted.
+	acceptEncoding = "gzip, br"
+)
+
+// UnsupportedEncodingError is returned for responses with a Content-Encoding that can't
+// be decoded, such as responses compressed with an encoding that was not accepted.
+type UnsupportedEncodingError struct {
+	Encoding string
+}
+
+func (e *UnsupportedEncodingError) Error() string {
+	return fmt.Sprintf("unsupported content encoding %q", e.Encoding)
+}
+
+// Replaces the body of the response with a reader that decodes it according to its
+// Content-Encoding header, and returns the ResponseTransfer of the response. The Size of
+// the transfer is updated with the received bytes as the body is being read.
+// As in the transparent decompression of the http.Transport, the Content-Encoding and
+// Content-Length headers are removed if the body is decoded.
+// It returns an UnsupportedEncodingError if the body can't be decoded, so its content
+// is not parsed.
+func decodeBody(resp *http.Response) (*models.ResponseTransfer, error) {
+	transfer := &models.ResponseTransfer{
+		Protocol:        resp.Proto,
+		ContentEncoding: strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))),
+	}
+
+	counter := &countingReader{Reader: resp.Body, n: &transfer.Size}
+
+	var decoded io.Reader
+	switch transfer.ContentEncoding {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(counter)
+		if err != nil && err != io.EOF {
+			return transfer, err
+		}
+
+		// An empty body can't be read by the gzip reader.
+		if err == io.EOF {
+			decoded = counter
+		} else {
+			decoded = gz
+		}
+	case "br":
+		decoded = brotli.NewReader(counter)
+	case "", "identity":
+		resp.Body = &decodedBody{Reader: counter, Closer: resp.Body}
+
+		return transfer, nil
+	default:
+		return transfer, &UnsupportedEncodingError{Encoding: transfer.ContentEncoding}
+	}
+
+	resp.Body = &decodedBody{Reader: decoded, Closer: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return transfer, nil
+}
+
+// countingReader adds the number of bytes read to n.
+type countingReader struct {
+	io.Reader
+	n *int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	*r.n += n
+
+	return n, err
+}
+
+// decodedBody reads the decoded body and closes the original body of the response.
+type decodedBody struct {
+	io.Reader
+	io.Closer
+}
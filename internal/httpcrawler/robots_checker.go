@@ -2,25 +2,37 @@ package httpcrawler
 
 import (
 	"errors"
+	"io"
+	"net/http"
 	"net/url"
 	"sync"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
 
 	"github.com/temoto/robotstxt"
 )
 
+// Max size of the robots.txt files, the rest of the file is ignored.
+const robotstxtMaxSize = 500 * 1024
+
 type RobotsChecker struct {
 	robotsMap map[string]*robotstxt.RobotsData
+	files     map[string]*models.RobotstxtFile
 	rlock     *sync.RWMutex
 	client    Client
 	userAgent string
+	token     string // Bot token used to test the URLs, it's the user agent unless it is set
 }
 
 func NewRobotsChecker(client Client, ua string) *RobotsChecker {
 	return &RobotsChecker{
 		robotsMap: make(map[string]*robotstxt.RobotsData),
+		files:     make(map[string]*models.RobotstxtFile),
 		rlock:     &sync.RWMutex{},
 		client:    client,
 		userAgent: ua,
+		token:     ua,
 	}
 }
 
@@ -36,7 +48,7 @@ func (r *RobotsChecker) IsBlocked(u *url.URL) bool {
 		path += "?" + u.Query().Encode()
 	}
 
-	return !robot.TestAgent(path, r.userAgent)
+	return !robot.TestAgent(path, r.token)
 }
 
 // Returns true if the robots.txt file exists and is valid
@@ -53,16 +65,63 @@ func (r *RobotsChecker) Exists(u *url.URL) bool {
 	return true
 }
 
+// Returns the Crawl-delay of the robots.txt file for the checker's user agent.
+// It returns 0 if the robots.txt file doesn't exist or it doesn't have a Crawl-delay.
+func (r *RobotsChecker) CrawlDelay(u *url.URL) time.Duration {
+	robot, err := r.getRobotsMap(u)
+	if err != nil || robot == nil {
+		return 0
+	}
+
+	group := robot.FindGroup(r.userAgent)
+	if group == nil {
+		return 0
+	}
+
+	return group.CrawlDelay
+}
+
 // Returns a list of sitemaps found in the robots.txt file
 func (r *RobotsChecker) GetSitemaps(u *url.URL) []string {
 	robot, err := r.getRobotsMap(u)
-	if err != nil {
+	if err != nil || robot == nil {
 		return []string{}
 	}
 
 	return robot.Sitemaps
 }
 
+// Returns the robots.txt file of the URL's host as it is used by the checker.
+// It returns nil if the file couldn't be fetched.
+func (r *RobotsChecker) File(u *url.URL) *models.RobotstxtFile {
+	r.getRobotsMap(u)
+
+	r.rlock.RLock()
+	defer r.rlock.RUnlock()
+
+	return r.files[u.Host]
+}
+
+// Sets the bot token the URLs are tested for instead of the checker's user agent.
+// The Crawl-delay is still the one of the checker's user agent, as it's the one making the requests.
+func (r *RobotsChecker) SetToken(token string) {
+	r.token = token
+}
+
+// Sets a draft robots.txt file for the host, which is used instead of the host's live file.
+// If the draft can't be parsed all the URLs are allowed, as if the host had no robots.txt file.
+func (r *RobotsChecker) SetDraft(host, body string) {
+	robot, err := robotstxt.FromString(body)
+	if err != nil {
+		robot, _ = robotstxt.FromStatusAndString(http.StatusNotFound, "")
+	}
+
+	r.rlock.Lock()
+	r.robotsMap[host] = robot
+	r.files[host] = &models.RobotstxtFile{Host: host, StatusCode: http.StatusOK, Draft: true, Body: body}
+	r.rlock.Unlock()
+}
+
 // Returns a RobotsData checking if it has already been created and stored in the robotsMap
 func (r *RobotsChecker) getRobotsMap(u *url.URL) (*robotstxt.RobotsData, error) {
 	r.rlock.RLock()
@@ -74,24 +133,34 @@ func (r *RobotsChecker) getRobotsMap(u *url.URL) (*robotstxt.RobotsData, error)
 		if err != nil {
 			r.rlock.Lock()
 			r.robotsMap[u.Host] = nil
+			r.files[u.Host] = &models.RobotstxtFile{Host: u.Host}
 			r.rlock.Unlock()
 
 			return nil, err
 		}
 		defer resp.Body.Close()
 
+		// Only the body of existing files is kept, so error pages are not stored.
+		file := &models.RobotstxtFile{Host: u.Host, StatusCode: resp.StatusCode}
+
 		if resp.StatusCode != 200 {
-			r.rlock.Lock()
-			r.robotsMap[u.Host] = nil
-			r.rlock.Unlock()
+			err = errors.New("robots.txt file does not exist")
+		}
+
+		var body []byte
+		if err == nil {
+			body, err = io.ReadAll(io.LimitReader(resp.Body, robotstxtMaxSize))
+			file.Body = string(body)
+		}
 
-			return nil, errors.New("robots.txt file does not exist")
+		if err == nil {
+			robot, err = robotstxt.FromBytes(body)
 		}
 
-		robot, err = robotstxt.FromResponse(resp)
 		if err != nil {
 			r.rlock.Lock()
 			r.robotsMap[u.Host] = nil
+			r.files[u.Host] = file
 			r.rlock.Unlock()
 
 			return nil, err
@@ -99,6 +168,7 @@ func (r *RobotsChecker) getRobotsMap(u *url.URL) (*robotstxt.RobotsData, error)
 
 		r.rlock.Lock()
 		r.robotsMap[u.Host] = robot
+		r.files[u.Host] = file
 		r.rlock.Unlock()
 	}
 
@@ -0,0 +1,125 @@
+package httpcrawler_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// RobotsClient responds to the requests with the robots.txt body in the files map
+// for the request's URL. Requests to URLs not in the map fail with an error.
+type RobotsClient struct {
+	MockClient
+	files map[string]string
+}
+
+func (c *RobotsClient) Get(u string) (*http.Response, error) {
+	body, ok := c.files[u]
+	if !ok {
+		return nil, errors.New("connection failed")
+	}
+
+	status := http.StatusOK
+	if body == "" {
+		status = http.StatusNotFound
+	}
+
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestRobotsChecker(t *testing.T) {
+	client := &RobotsClient{
+		files: map[string]string{
+			"https://example.com/robots.txt":     "User-agent: *\nDisallow: /private",
+			"https://www.example.com/robots.txt": "",
+		},
+	}
+
+	checker := httpcrawler.NewRobotsChecker(client, "SEOnaut")
+	checker.SetDraft("draft.example.com", "User-agent: *\nDisallow: /draft")
+
+	table := []struct {
+		url     string
+		blocked bool
+		file    *models.RobotstxtFile
+	}{
+		{
+			"https://example.com/private",
+			true,
+			&models.RobotstxtFile{Host: "example.com", StatusCode: http.StatusOK, Body: "User-agent: *\nDisallow: /private"},
+		},
+		{
+			"https://www.example.com/private",
+			false,
+			&models.RobotstxtFile{Host: "www.example.com", StatusCode: http.StatusNotFound},
+		},
+		{
+			"https://draft.example.com/draft",
+			true,
+			&models.RobotstxtFile{Host: "draft.example.com", StatusCode: http.StatusOK, Draft: true, Body: "User-agent: *\nDisallow: /draft"},
+		},
+		{
+			"https://other.example.com/private",
+			false,
+			&models.RobotstxtFile{Host: "other.example.com"},
+		},
+	}
+
+	for _, tc := range table {
+		u, _ := url.Parse(tc.url)
+
+		if blocked := checker.IsBlocked(u); blocked != tc.blocked {
+			t.Errorf("IsBlocked %s: %v want %v", tc.url, blocked, tc.blocked)
+		}
+
+		if file := checker.File(u); *file != *tc.file {
+			t.Errorf("File %s: %+v want %+v", tc.url, file, tc.file)
+		}
+	}
+}
+
+func TestRobotsCheckerToken(t *testing.T) {
+	client := &RobotsClient{
+		files: map[string]string{
+			"https://example.com/robots.txt": "User-agent: *\nDisallow: /private\n\nUser-agent: Googlebot\nDisallow: /google",
+		},
+	}
+
+	checker := httpcrawler.NewRobotsChecker(client, "Mozilla/5.0 (compatible; SEOnautBot/1.0)")
+	checker.SetToken("Googlebot")
+
+	table := []struct {
+		url     string
+		blocked bool
+	}{
+		{"https://example.com/private", false},
+		{"https://example.com/google", true},
+	}
+
+	for _, tc := range table {
+		u, _ := url.Parse(tc.url)
+		if blocked := checker.IsBlocked(u); blocked != tc.blocked {
+			t.Errorf("IsBlocked %s: %v want %v", tc.url, blocked, tc.blocked)
+		}
+	}
+}
+
+func TestRobotsCheckerInvalidDraft(t *testing.T) {
+	checker := httpcrawler.NewRobotsChecker(&RobotsClient{}, "SEOnaut")
+	checker.SetDraft("example.com", "Disallow: /private")
+
+	u, _ := url.Parse("https://example.com/private")
+	if checker.IsBlocked(u) {
+		t.Errorf("IsBlocked %s: true want false", u)
+	}
+
+	if sitemaps := checker.GetSitemaps(u); len(sitemaps) != 0 {
+		t.Errorf("GetSitemaps %s: %v want none", u, sitemaps)
+	}
+}
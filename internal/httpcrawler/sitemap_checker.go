@@ -1,12 +1,32 @@
 package httpcrawler
 
 import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
 	"errors"
+	"io"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
-	sitemap "github.com/oxffaa/gopher-parse-sitemap"
+	"github.com/stjudewashere/seonaut/internal/models"
 )
 
+// Max number of levels of nested sitemap indexes that are followed.
+const maxSitemapDepth = 3
+
+// Layouts of the W3C Datetime formats allowed in the lastmod values.
+var lastmodLayouts = []string{
+	"2006",
+	"2006-01",
+	"2006-01-02",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z07:00",
+}
+
 type SitemapChecker struct {
 	limit  int
 	client Client
@@ -40,64 +60,289 @@ func (sc *SitemapChecker) urlExists(URL string) bool {
 	return resp.StatusCode >= 200 && resp.StatusCode < 300
 }
 
+// sitemapAudit keeps the state shared by the sitemap files parsed concurrently.
+type sitemapAudit struct {
+	lock     sync.Mutex
+	seen     map[string]bool
+	parsed   map[string]bool
+	count    int
+	files    []*models.SitemapFile
+	callback func(u string)
+}
+
 // Parse the sitemaps using a callback function on each entry
-// For each URL provided check if it's an index sitemap
-func (sc *SitemapChecker) ParseSitemaps(URLs []string, callback func(u string)) {
-	c := 0
+// For each URL provided check if it's an index sitemap, in which case the sitemaps
+// it lists are parsed instead. Nested sitemap indexes are followed up to maxSitemapDepth
+// levels and each sitemap file is only parsed once.
+// The parsing stops once the context is done.
+// It returns the audit of every sitemap file fetched.
+func (sc *SitemapChecker) ParseSitemaps(ctx context.Context, URLs []string, callback func(u string)) []*models.SitemapFile {
+	audit := &sitemapAudit{
+		seen:     make(map[string]bool),
+		parsed:   make(map[string]bool),
+		callback: callback,
+	}
+
 	wg := new(sync.WaitGroup)
-	lock := sync.RWMutex{}
 
 	for _, l := range URLs {
-		sitemaps := sc.checkIndex(l)
-		for _, s := range sitemaps {
-			wg.Add(1)
-
-			// Each sitemap is parsed in its own Go routine
-			// If the sitemap limit is hit the parser function returns an error to stop the process
-			go func(s string) {
-				resp, err := sc.client.Get(s)
-				if err != nil {
-					return
-				}
-				defer resp.Body.Close()
+		if audit.parse(l) {
+			sc.parseTree(ctx, audit, wg, l, "", 0)
+		}
+	}
 
-				sitemap.Parse(resp.Body, func(e sitemap.Entry) error {
-					callback(e.GetLocation())
+	wg.Wait()
 
-					lock.Lock()
-					defer lock.Unlock()
+	return audit.files
+}
 
-					c++
-					if c >= sc.limit {
-						return errors.New("URL limit hit")
-					}
+// Parses the sitemap file u, listed in the index sitemap if it is not empty. If it is a
+// sitemap index, each of the sitemaps it lists is parsed in its own Go routine.
+func (sc *SitemapChecker) parseTree(ctx context.Context, audit *sitemapAudit, wg *sync.WaitGroup, u, index string, depth int) {
+	if ctx.Err() != nil {
+		return
+	}
 
-					return nil
-				})
+	file, sitemaps := sc.parseFile(ctx, audit, u, index, depth < maxSitemapDepth)
+	audit.add(file)
 
-				wg.Done()
-			}(s)
+	for _, s := range sitemaps {
+		if !audit.parse(s) {
+			continue
 		}
-	}
 
-	wg.Wait()
+		wg.Add(1)
+		go func(s string) {
+			defer wg.Done()
+
+			sc.parseTree(ctx, audit, wg, s, u, depth+1)
+		}(s)
+	}
 }
 
-// Returns a slice of strings with sitemap URLs
-// If URL is a sitemap index the slice will contain all the sitemaps found
-// Otherwise it will return an slice containing only the original URL
-func (sc *SitemapChecker) checkIndex(URL string) []string {
+// Fetches and parses the sitemap file u, which is listed in the index sitemap if it is not empty.
+// The URLs of the sitemap entries are sent to the audit's callback until the checker's limit is hit.
+// If the file is a sitemap index and followIndex is true, it returns the URLs of the sitemaps it lists.
+func (sc *SitemapChecker) parseFile(ctx context.Context, audit *sitemapAudit, u, index string, followIndex bool) (*models.SitemapFile, []string) {
+	file := &models.SitemapFile{URL: u, Index: index}
 	sitemaps := []string{}
 
-	sitemap.ParseIndexFromSite(URL, func(e sitemap.IndexEntry) error {
-		l := e.GetLocation()
-		sitemaps = append(sitemaps, l)
-		return nil
+	resp, err := sc.client.Get(u)
+	if err != nil {
+		file.FetchError = FetchError(err)
+		return file, sitemaps
+	}
+	defer resp.Body.Close()
+
+	file.StatusCode = resp.StatusCode
+	file.ContentType = resp.Header.Get("Content-Type")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return file, sitemaps
+	}
+
+	buffered := bufio.NewReader(resp.Body)
+
+	var body io.Reader = buffered
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			file.ParseError = err.Error()
+			return file, sitemaps
+		}
+		defer gz.Close()
+
+		file.Compressed = true
+		body = gz
+	}
+
+	host := ""
+	if parsed, err := url.Parse(u); err == nil {
+		host = parsed.Host
+	}
+
+	now := time.Now()
+	limited := &contextReader{ctx: ctx, Reader: io.LimitReader(body, models.SitemapMaxSize+1)}
+	counter := &countingReader{Reader: limited, n: &file.Size}
+
+	err = parseSitemap(counter, func(e sitemapEntry) {
+		file.IsIndex = e.Index
+		file.URLs++
+
+		if e.Lastmod != "" {
+			t, ok := parseLastmod(e.Lastmod)
+			if !ok {
+				file.InvalidLastmod++
+			} else if t.After(now) {
+				file.FutureLastmod++
+			}
+		}
+
+		parsed, err := url.Parse(e.Loc)
+		if err != nil || parsed.Host != host {
+			file.ExternalURLs++
+		}
+
+		if e.Index {
+			if followIndex {
+				sitemaps = append(sitemaps, e.Loc)
+			}
+
+			return
+		}
+
+		if audit.seenURL(e.Loc) {
+			file.DuplicateURLs++
+			return
+		}
+
+		audit.send(e.Loc, sc.limit)
 	})
 
-	if len(sitemaps) == 0 {
-		sitemaps = append(sitemaps, URL)
+	// Read the rest of the file so its size can be checked.
+	io.Copy(io.Discard, counter)
+
+	if err != nil && file.Size <= models.SitemapMaxSize {
+		file.ParseError = err.Error()
+	}
+
+	return file, sitemaps
+}
+
+// contextReader stops reading once the context is done.
+type contextReader struct {
+	ctx context.Context
+	io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.Reader.Read(p)
+}
+
+// Adds the file to the audit's files.
+func (a *sitemapAudit) add(file *models.SitemapFile) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.files = append(a.files, file)
+}
+
+// Returns true if the sitemap file u has not been parsed yet, marking it as parsed.
+func (a *sitemapAudit) parse(u string) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.parsed[u] {
+		return false
+	}
+
+	a.parsed[u] = true
+
+	return true
+}
+
+// Returns true if the URL has already been found in any sitemap, otherwise it marks it as seen.
+func (a *sitemapAudit) seenURL(u string) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.seen[u] {
+		return true
+	}
+
+	a.seen[u] = true
+
+	return false
+}
+
+// Sends the URL to the callback unless the limit of URLs has been hit.
+func (a *sitemapAudit) send(u string, limit int) {
+	a.lock.Lock()
+	if a.count >= limit {
+		a.lock.Unlock()
+		return
+	}
+	a.count++
+	a.lock.Unlock()
+
+	a.callback(u)
+}
+
+// sitemapEntry is an entry of a sitemap, or of a sitemap index if Index is true.
+type sitemapEntry struct {
+	Loc     string
+	Lastmod string
+	Index   bool
+}
+
+// Parses a sitemap or a sitemap index calling fn with each of its entries.
+func parseSitemap(r io.Reader, fn func(sitemapEntry)) error {
+	decoder := xml.NewDecoder(r)
+	root := ""
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		se, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if root == "" {
+			root = se.Name.Local
+			if root != "urlset" && root != "sitemapindex" {
+				return errors.New("root element is not urlset or sitemapindex")
+			}
+
+			continue
+		}
+
+		if se.Name.Local != "url" && se.Name.Local != "sitemap" {
+			continue
+		}
+
+		entry := struct {
+			Loc     string `xml:"loc"`
+			Lastmod string `xml:"lastmod"`
+		}{}
+
+		if err := decoder.DecodeElement(&entry, &se); err != nil {
+			return err
+		}
+
+		loc := strings.TrimSpace(entry.Loc)
+		if loc == "" {
+			continue
+		}
+
+		fn(sitemapEntry{Loc: loc, Lastmod: strings.TrimSpace(entry.Lastmod), Index: root == "sitemapindex"})
+	}
+
+	if root == "" {
+		return errors.New("empty sitemap")
+	}
+
+	return nil
+}
+
+// Parses a lastmod value in any of the W3C Datetime formats.
+// It returns false if the value is not valid.
+func parseLastmod(v string) (time.Time, bool) {
+	for _, layout := range lastmodLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
 	}
 
-	return sitemaps
+	return time.Time{}, false
 }
@@ -0,0 +1,189 @@
+package httpcrawler_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// SitemapClient responds to GET requests with the body in the documents map,
+// or with a 404 response if the URL is not in the map.
+type SitemapClient struct {
+	MockClient
+	documents map[string][]byte
+}
+
+func (c *SitemapClient) Get(u string) (*http.Response, error) {
+	d, ok := c.documents[u]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/xml"}},
+		Body:       io.NopCloser(bytes.NewReader(d)),
+	}, nil
+}
+
+func TestParseSitemaps(t *testing.T) {
+	compressed := &bytes.Buffer{}
+	gz := gzip.NewWriter(compressed)
+	gz.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+			<url><loc>https://example.com/b</loc><lastmod>2999-01-01</lastmod></url>
+			<url><loc>https://example.com/a</loc></url>
+		</urlset>`))
+	gz.Close()
+
+	client := &SitemapClient{
+		documents: map[string][]byte{
+			"https://example.com/sitemap.xml": []byte(`<?xml version="1.0" encoding="UTF-8"?>
+				<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+					<sitemap><loc>https://example.com/sitemap-1.xml</loc></sitemap>
+					<sitemap><loc>https://example.com/sitemap-2.xml.gz</loc></sitemap>
+					<sitemap><loc>https://example.com/sitemap-3.xml</loc></sitemap>
+				</sitemapindex>`),
+			"https://example.com/sitemap-1.xml": []byte(`<?xml version="1.0" encoding="UTF-8"?>
+				<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+					<url><loc>https://example.com/a</loc><lastmod>2023-05-10T10:00:00+00:00</lastmod></url>
+					<url><loc>https://other.example.com/</loc><lastmod>10/05/2023</lastmod></url>
+				</urlset>`),
+			"https://example.com/sitemap-2.xml.gz": compressed.Bytes(),
+		},
+	}
+
+	checker := httpcrawler.NewSitemapChecker(client, 100)
+
+	lock := sync.Mutex{}
+	urls := []string{}
+	files := checker.ParseSitemaps(context.Background(), []string{"https://example.com/sitemap.xml"}, func(u string) {
+		lock.Lock()
+		defer lock.Unlock()
+		urls = append(urls, u)
+	})
+
+	sort.Strings(urls)
+	expectedURLs := []string{"https://example.com/a", "https://example.com/b", "https://other.example.com/"}
+	if len(urls) != len(expectedURLs) {
+		t.Fatalf("Expected URLs %v, got %v", expectedURLs, urls)
+	}
+
+	for i := range urls {
+		if urls[i] != expectedURLs[i] {
+			t.Errorf("Expected URL %s, got %s", expectedURLs[i], urls[i])
+		}
+	}
+
+	if len(files) != 4 {
+		t.Fatalf("Expected 4 sitemap files, got %d", len(files))
+	}
+
+	byURL := make(map[string]*models.SitemapFile)
+	for _, f := range files {
+		byURL[f.URL] = f
+	}
+
+	index := byURL["https://example.com/sitemap.xml"]
+	if !index.IsIndex || index.URLs != 3 || index.Index != "" {
+		t.Errorf("Unexpected index %+v", index)
+	}
+
+	first := byURL["https://example.com/sitemap-1.xml"]
+	if first.Index != index.URL || first.URLs != 2 || first.InvalidLastmod != 1 || first.ExternalURLs != 1 {
+		t.Errorf("Unexpected sitemap %+v", first)
+	}
+
+	second := byURL["https://example.com/sitemap-2.xml.gz"]
+	if !second.Compressed || second.URLs != 2 || second.FutureLastmod != 1 || second.Size == 0 {
+		t.Errorf("Unexpected compressed sitemap %+v", second)
+	}
+
+	if first.DuplicateURLs+second.DuplicateURLs != 1 {
+		t.Errorf("Expected 1 duplicate URL, got %d", first.DuplicateURLs+second.DuplicateURLs)
+	}
+
+	missing := byURL["https://example.com/sitemap-3.xml"]
+	if missing.StatusCode != http.StatusNotFound || missing.URLs != 0 {
+		t.Errorf("Unexpected missing sitemap %+v", missing)
+	}
+}
+
+func TestParseNestedSitemaps(t *testing.T) {
+	index := func(locs ...string) []byte {
+		b := []byte(`<?xml version="1.0" encoding="UTF-8"?><sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		for _, l := range locs {
+			b = append(b, []byte("<sitemap><loc>"+l+"</loc></sitemap>")...)
+		}
+
+		return append(b, []byte("</sitemapindex>")...)
+	}
+
+	client := &SitemapClient{
+		documents: map[string][]byte{
+			"https://example.com/sitemap.xml":   index("https://example.com/index-1.xml", "https://example.com/sitemap.xml"),
+			"https://example.com/index-1.xml":   index("https://example.com/index-2.xml", "https://example.com/sitemap-1.xml"),
+			"https://example.com/index-2.xml":   index("https://example.com/index-3.xml"),
+			"https://example.com/index-3.xml":   index("https://example.com/sitemap-4.xml"),
+			"https://example.com/sitemap-1.xml": []byte(`<urlset><url><loc>https://example.com/a</loc></url></urlset>`),
+		},
+	}
+
+	checker := httpcrawler.NewSitemapChecker(client, 100)
+
+	urls := []string{}
+	files := checker.ParseSitemaps(context.Background(), []string{"https://example.com/sitemap.xml"}, func(u string) {
+		urls = append(urls, u)
+	})
+
+	if len(urls) != 1 || urls[0] != "https://example.com/a" {
+		t.Errorf("Expected the URL of the nested sitemap, got %v", urls)
+	}
+
+	parsed := []string{}
+	for _, f := range files {
+		parsed = append(parsed, f.URL)
+	}
+	sort.Strings(parsed)
+
+	expected := []string{
+		"https://example.com/index-1.xml",
+		"https://example.com/index-2.xml",
+		"https://example.com/index-3.xml",
+		"https://example.com/sitemap-1.xml",
+		"https://example.com/sitemap.xml",
+	}
+
+	if strings.Join(parsed, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected sitemap files %v, got %v", expected, parsed)
+	}
+}
+
+func TestParseSitemapsCancelled(t *testing.T) {
+	client := &SitemapClient{
+		documents: map[string][]byte{
+			"https://example.com/sitemap.xml": []byte(`<urlset><url><loc>https://example.com/a</loc></url></urlset>`),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checker := httpcrawler.NewSitemapChecker(client, 100)
+	files := checker.ParseSitemaps(ctx, []string{"https://example.com/sitemap.xml"}, func(u string) {
+		t.Errorf("Unexpected URL %s in a cancelled parse", u)
+	})
+
+	if len(files) != 0 {
+		t.Errorf("Expected no sitemap files, got %d", len(files))
+	}
+}
@@ -8,7 +8,7 @@ import (
 type Crawl struct {
 	Id                    int64
 	ProjectId             int64
-	URL                   string
+	URL                   string // Start URL, normalized by the crawler when the crawl starts
 	Start                 time.Time
 	End                   sql.NullTime
 	TotalIssues           int
@@ -18,6 +18,7 @@ type Crawl struct {
 	AlertIssues           int
 	WarningIssues         int
 	BlockedByRobotstxt    int // URLs blocked by robots.txt
+	ExcludedByRules       int // URLs not crawled because of the project's include and exclude rules
 	Noindex               int // URLS with noindex attribute
 	SitemapExists         bool
 	RobotstxtExists       bool
@@ -27,4 +28,6 @@ type Crawl struct {
 	ExternalNoFollowLinks int
 	SponsoredLinks        int
 	UGCLinks              int
+	ListMode              bool  // Only the URLs of a list are crawled, without following links
+	ParentId              int64 // Set in mobile crawls, it's the id of the crawl they are compared with
 }
@@ -0,0 +1,13 @@
+package models
+
+// Crawl statuses sent in the CrawlEndMessage.
+const (
+	CrawlCompleted = "completed"
+	CrawlCancelled = "cancelled"
+)
+
+// CrawlEndMessage is sent once the crawl and its report have been completed.
+type CrawlEndMessage struct {
+	TotalURLs int
+	Status    string
+}
@@ -0,0 +1,11 @@
+package models
+
+// ExternalLinkStatus stores the response of an external URL found in the links of a crawl.
+// RedirectURL is set if the URL redirects to another URL, and FetchError is set
+// if the URL could not be fetched, in which case StatusCode is 0.
+type ExternalLinkStatus struct {
+	URL         string
+	StatusCode  int
+	RedirectURL string
+	FetchError  string
+}
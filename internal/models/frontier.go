@@ -0,0 +1,18 @@
+package models
+
+// Frontier is a checkpoint of the crawler's state used to resume a crawl
+// that was interrupted before it was completed. The checkpoints sent by the crawler
+// only include the URLs seen since the previous checkpoint, while the stored frontier
+// includes all of them.
+type Frontier struct {
+	Queued       []FrontierURL // URLs waiting to be crawled
+	Seen         []string      // URLs already seen by the crawler, including the queued ones
+	Crawled      int           // Number of URLs crawled
+	PageReportId int64         // Id of the last page report saved before the checkpoint
+}
+
+// FrontierURL is an URL waiting to be crawled.
+type FrontierURL struct {
+	URL   string
+	Depth int
+}
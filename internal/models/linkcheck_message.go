@@ -0,0 +1,7 @@
+package models
+
+// LinkCheckMessage is sent as the external links of a crawl are checked.
+type LinkCheckMessage struct {
+	Checked int
+	Total   int
+}
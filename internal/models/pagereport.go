@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"net/url"
 )
 
@@ -16,9 +17,12 @@ type PageReport struct {
 	Lang               string
 	Title              string
 	Description        string
-	Robots             string
+	Robots             string // Robots directives that apply to the crawl's bot token
 	Noindex            bool
 	Nofollow           bool
+	Nosnippet          bool
+	MaxSnippet         int          // Max length of the search result snippets, 0 if there is no limit
+	UnavailableAfter   sql.NullTime // Date after which the page should not be shown in search results
 	Canonical          string
 	H1                 string
 	H2                 string
@@ -40,4 +44,13 @@ type PageReport struct {
 	InternalLinks      []InternalLink
 	ValidLang          bool
 	Depth              int
+	FetchError         string // Type of error if the URL could not be fetched
+	RedirectHops       []RedirectHop
+	Variants           []string    // URLs normalized into this page's URL
+	RenderDiff         *RenderDiff // Set if the page was rendered executing its JavaScript
+	Timing             ResponseTiming
+	Transfer           ResponseTransfer
+	TLS                *TLSCertificate // Certificate of the host of https URLs, stored once per host
+	ContentHash        string          // Hash of the visible text of HTML pages
+	SimHash            uint64          // Fingerprint of the visible text to find near-duplicates
 }
@@ -6,10 +6,24 @@ import (
 	"golang.org/x/net/html"
 )
 
+// PageReportMessage is sent by the crawler for each new PageReport.
+// Periodically, the crawler also sends a message with a Frontier checkpoint
+// instead of a PageReport. URLs excluded by the project's rules are sent in
+// the Excluded field, without a PageReport, so they can be counted. The same way, URLs
+// normalized into a different URL are sent in the Variant field so they can be stored,
+// and the TLS certificate of each crawled host is sent once in the Certificate field.
+// The audit of each sitemap file parsed by the crawler is sent in the Sitemap field,
+// and the robots.txt file of each crawled host is sent once in the Robotstxt field.
 type PageReportMessage struct {
-	PageReport *PageReport
-	HtmlNode   *html.Node
-	Header     *http.Header
-	Crawled    int
-	Discovered int
+	PageReport  *PageReport
+	HtmlNode    *html.Node
+	Header      *http.Header
+	Crawled     int
+	Discovered  int
+	Frontier    *Frontier
+	Excluded    string // URL not crawled because of the project's include and exclude rules
+	Variant     *URLVariant
+	Certificate *TLSCertificate
+	Sitemap     *SitemapFile
+	Robotstxt   *RobotstxtFile
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"time"
 )
 
@@ -18,4 +19,28 @@ type Project struct {
 	BasicAuth       bool
 	AuthUser        string
 	AuthPass        string
+	KeepCrawls      int
+	Schedule        string
+	NextCrawl       sql.NullTime
+	MaxPageReports  int // Max number of page reports created in each crawl
+	MaxDepth        int // Max depth of the crawled URLs, 0 means no limit
+	CrawlThreads    int // Number of concurrent requests
+	CrawlDelay      int // Max random delay before each request in milliseconds
+	CrawlTimeout    int // HTTP requests timeout in seconds
+	RenderJS        bool
+	CrawlHeaders    string // Custom request headers, one "Name: value" header per line
+	UseCookies      bool
+	LoginURL        string // If set, the LoginData form is posted to this URL before crawling
+	LoginData       string // URL encoded login form data, requested when the crawl starts and not stored
+	IncludeRules    string // If set, only the URLs matching any of these rules are crawled, one rule per line
+	ExcludeRules    string // URLs matching any of these rules are not crawled, one rule per line
+	NormalizeURLs   bool
+	StripParams     string // Query parameters removed from the normalized URLs, separated by commas
+	IgnoreParams    bool   // If true, the query string is removed from the normalized URLs
+	ProxyURL        string // If set, overrides the server's proxy for the project's crawls
+	ProxyAuth       bool   // If true, the proxy credentials are requested when the crawl starts and not stored
+	MobileCrawl     bool   // If true, the crawled URLs are crawled again with the mobile user agent
+	CheckLinks      bool   // If true, the status of the external URLs is checked after the crawl
+	RobotsDraft     string // If set, it is used instead of the live robots.txt file of the project's host
+	RobotsToken     string // If set, robots directives are evaluated for this bot token instead of the crawler's user agent
 }
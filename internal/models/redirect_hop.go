@@ -0,0 +1,11 @@
+package models
+
+// RedirectHop is a request of a redirect chain. The first hop is the URL of the page report,
+// and Location is the URL of the next hop. The last hop has an empty Location unless the
+// chain is a loop or it was too long to be followed.
+type RedirectHop struct {
+	Hop        int
+	URL        string
+	StatusCode int
+	Location   string
+}
@@ -0,0 +1,13 @@
+package models
+
+// RenderDiff compares the page report of the raw HTML returned by the server
+// with the page report of the DOM rendered executing the page's JavaScript.
+type RenderDiff struct {
+	RawTitle      string
+	RenderedTitle string
+	RawLinks      int
+	RenderedLinks int
+	AddedLinks    int // Links found only in the rendered DOM
+	RawWords      int
+	RenderedWords int
+}
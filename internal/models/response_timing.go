@@ -0,0 +1,13 @@
+package models
+
+// ResponseTiming stores the time in milliseconds spent in each phase of the request of a URL.
+// DNS, Connect and TLS are zero if the request reused an open connection.
+// TTFB is the time to the first byte of the response and Download is the total time
+// from the start of the request until its body has been read.
+type ResponseTiming struct {
+	DNS      int
+	Connect  int
+	TLS      int
+	TTFB     int
+	Download int
+}
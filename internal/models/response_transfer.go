@@ -0,0 +1,10 @@
+package models
+
+// ResponseTransfer describes how a response was transferred. Protocol is the HTTP
+// version of the response, ContentEncoding is the compression of the body if any,
+// and Size is the number of bytes of the body as they were received, before decompressing it.
+type ResponseTransfer struct {
+	Protocol        string
+	ContentEncoding string
+	Size            int
+}
@@ -0,0 +1,11 @@
+package models
+
+// RobotstxtFile stores the robots.txt file of a host as it was used in a crawl.
+// StatusCode is 0 if the file could not be fetched, and Draft is true if the project's
+// draft robots.txt was used instead of the live file.
+type RobotstxtFile struct {
+	Host       string
+	StatusCode int
+	Draft      bool
+	Body       string
+}
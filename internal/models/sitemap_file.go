@@ -0,0 +1,31 @@
+package models
+
+const (
+	// Max number of URLs allowed in a sitemap file.
+	SitemapMaxURLs = 50000
+
+	// Max size of a sitemap file in bytes once it has been uncompressed.
+	SitemapMaxSize = 50 * 1024 * 1024
+)
+
+// SitemapFile stores the audit of a sitemap file fetched in a crawl. Index is the URL of the
+// sitemap index that lists the file, if any, and IsIndex is true if the file is a sitemap index.
+// Size is the uncompressed size of the file, which is only read up to SitemapMaxSize + 1 bytes.
+// URLs is the number of entries of the file, and the other counters are the number of entries
+// with each problem. DuplicateURLs counts the entries already found in the same or other files.
+type SitemapFile struct {
+	URL            string
+	Index          string
+	IsIndex        bool
+	StatusCode     int
+	ContentType    string
+	Compressed     bool
+	Size           int
+	URLs           int
+	InvalidLastmod int
+	FutureLastmod  int
+	ExternalURLs   int
+	DuplicateURLs  int
+	FetchError     string
+	ParseError     string
+}
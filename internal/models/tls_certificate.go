@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// TLSCertificate stores the certificate a host presented in the TLS handshake of a crawl.
+// ValidChain is false if the certificate chain doesn't verify up to a trusted root,
+// in which case ChainError contains the reason. HostnameMatch is false if the certificate
+// is not valid for the Host, and Version is the negotiated TLS version.
+type TLSCertificate struct {
+	Host          string
+	Subject       string
+	Issuer        string
+	NotAfter      time.Time
+	DNSNames      []string
+	ValidChain    bool
+	ChainError    string
+	HostnameMatch bool
+	Version       string
+}
@@ -0,0 +1,7 @@
+package models
+
+// URLVariant is an URL found by the crawler that was normalized into a different URL.
+type URLVariant struct {
+	URL     string // Normalized URL
+	Variant string // URL as it was found
+}
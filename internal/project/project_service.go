@@ -3,9 +3,32 @@ package project
 import (
 	"errors"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/cache_manager"
+	"github.com/stjudewashere/seonaut/internal/httpcrawler"
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/scheduler"
+	"github.com/stjudewashere/seonaut/internal/urlfilter"
+
+	"github.com/temoto/robotstxt"
+)
+
+const (
+	// Number of crawls kept by default for each project.
+	DefaultKeepCrawls = 2
+
+	// Max number of crawls that can be kept for each project.
+	MaxKeepCrawls = 10
+
+	// Default crawl limits for each project.
+	// The server-wide limits are set in the crawler's config.
+	DefaultMaxPageReports = 20000
+	DefaultMaxDepth       = 0
+	DefaultCrawlThreads   = 2
+	DefaultCrawlDelay     = 1500
+	DefaultCrawlTimeout   = 10
 )
 
 type Storage interface {
@@ -39,6 +62,17 @@ func (s *Service) SaveProject(project *models.Project, userId int) error {
 		return errors.New("Protocol not supported")
 	}
 
+	project.KeepCrawls = keepCrawlsLimit(project.KeepCrawls)
+	crawlLimits(project)
+
+	if err := crawlAuth(project); err != nil {
+		return err
+	}
+
+	if _, err := urlfilter.New(project.IncludeRules, project.ExcludeRules); err != nil {
+		return err
+	}
+
 	s.storage.SaveProject(project, userId)
 
 	return nil
@@ -70,6 +104,111 @@ func (s *Service) DeleteProject(p *models.Project) {
 }
 
 // Update project details.
+// It returns an error if the project's crawl schedule or robots.txt draft is not valid.
 func (s *Service) UpdateProject(p *models.Project) error {
+	p.KeepCrawls = keepCrawlsLimit(p.KeepCrawls)
+	crawlLimits(p)
+
+	if err := crawlAuth(p); err != nil {
+		return err
+	}
+
+	if _, err := urlfilter.New(p.IncludeRules, p.ExcludeRules); err != nil {
+		return err
+	}
+
+	if _, err := robotstxt.FromString(p.RobotsDraft); err != nil {
+		return err
+	}
+
+	nextCrawl, err := scheduler.NextCrawl(p.Schedule, time.Now())
+	if err != nil {
+		return err
+	}
+
+	p.NextCrawl = nextCrawl
+
 	return s.storage.UpdateProject(p)
 }
+
+// Returns the number of crawls to keep within the allowed limits.
+// If it is not set it returns the default value.
+func keepCrawlsLimit(n int) int {
+	if n < 1 {
+		return DefaultKeepCrawls
+	}
+
+	return min(n, MaxKeepCrawls)
+}
+
+// Sets the project's crawl limits that are not valid to their default values.
+func crawlLimits(p *models.Project) {
+	if p.MaxPageReports < 1 {
+		p.MaxPageReports = DefaultMaxPageReports
+	}
+
+	if p.MaxDepth < 0 {
+		p.MaxDepth = DefaultMaxDepth
+	}
+
+	if p.CrawlThreads < 1 {
+		p.CrawlThreads = DefaultCrawlThreads
+	}
+
+	if p.CrawlDelay < 0 {
+		p.CrawlDelay = DefaultCrawlDelay
+	}
+
+	if p.CrawlTimeout < 1 {
+		p.CrawlTimeout = DefaultCrawlTimeout
+	}
+}
+
+// Returns an error if the project's custom headers, proxy or login settings are not valid.
+// JavaScript rendering is not allowed in projects with their own proxy.
+// The proxy credentials are not stored, they are requested when the crawl starts.
+// The login URL must be in the same host as the project's URL.
+func crawlAuth(p *models.Project) error {
+	if _, err := httpcrawler.ParseHeaders(p.CrawlHeaders); err != nil {
+		return err
+	}
+
+	proxyURL, err := httpcrawler.ParseProxy(p.ProxyURL)
+	if err != nil {
+		return err
+	}
+
+	if proxyURL != nil && proxyURL.User != nil {
+		return errors.New("proxy URL can't include the credentials")
+	}
+
+	if p.ProxyAuth && proxyURL == nil {
+		return errors.New("proxy authentication requires a project proxy")
+	}
+
+	// The headless browser uses its own proxy settings, so the rendered pages
+	// would not be requested through the project's proxy.
+	if p.RenderJS && p.ProxyURL != "" {
+		return errors.New("JavaScript rendering can't be used with a project proxy")
+	}
+
+	if p.LoginURL == "" {
+		return nil
+	}
+
+	loginURL, err := url.ParseRequestURI(p.LoginURL)
+	if err != nil {
+		return err
+	}
+
+	projectURL, err := url.Parse(p.URL)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimPrefix(loginURL.Host, "www.") != strings.TrimPrefix(projectURL.Host, "www.") {
+		return errors.New("login URL must be in the project's domain")
+	}
+
+	return nil
+}
@@ -7,20 +7,22 @@ import (
 )
 
 type Queue struct {
-	in     chan *httpcrawler.RequestMessage
-	out    chan *httpcrawler.RequestMessage
-	ack    chan string
-	count  chan int
-	active chan bool
+	in       chan *httpcrawler.RequestMessage
+	out      chan *httpcrawler.RequestMessage
+	ack      chan string
+	count    chan int
+	active   chan bool
+	snapshot chan chan []*httpcrawler.RequestMessage
 }
 
 func New(ctx context.Context) *Queue {
 	q := Queue{
-		in:     make(chan *httpcrawler.RequestMessage),
-		out:    make(chan *httpcrawler.RequestMessage),
-		ack:    make(chan string),
-		count:  make(chan int),
-		active: make(chan bool),
+		in:       make(chan *httpcrawler.RequestMessage),
+		out:      make(chan *httpcrawler.RequestMessage),
+		ack:      make(chan string),
+		count:    make(chan int),
+		active:   make(chan bool),
+		snapshot: make(chan chan []*httpcrawler.RequestMessage),
 	}
 
 	go q.manage(ctx)
@@ -39,7 +41,7 @@ func (q *Queue) manage(ctx context.Context) {
 	}()
 
 	queue := []*httpcrawler.RequestMessage{}
-	active := make(map[string]bool)
+	active := make(map[string]*httpcrawler.RequestMessage)
 
 	var first *httpcrawler.RequestMessage
 	var out chan *httpcrawler.RequestMessage
@@ -47,7 +49,7 @@ func (q *Queue) manage(ctx context.Context) {
 	for {
 		if first == nil && len(queue) > 0 {
 			first = queue[0]
-			active[first.URL] = true
+			active[first.URL] = first
 			queue = queue[1:]
 		}
 
@@ -68,6 +70,12 @@ func (q *Queue) manage(ctx context.Context) {
 			first = nil
 		case v := <-q.ack:
 			delete(active, v)
+		case r := <-q.snapshot:
+			items := make([]*httpcrawler.RequestMessage, 0, len(active)+len(queue))
+			for _, v := range active {
+				items = append(items, v)
+			}
+			r <- append(items, queue...)
 		}
 	}
 }
@@ -97,6 +105,15 @@ func (q *Queue) Count() int {
 	return v
 }
 
+// Snapshot returns the elements in the queue, including the active elements
+// that have not been acknowledged yet.
+func (q *Queue) Snapshot() []*httpcrawler.RequestMessage {
+	r := make(chan []*httpcrawler.RequestMessage)
+	q.snapshot <- r
+
+	return <-r
+}
+
 // Active returns true if the queue is not empty or has active elements.
 func (q *Queue) Active() bool {
 	return <-q.active
@@ -54,3 +54,32 @@ func TestActiveNotActive(t *testing.T) {
 		t.Errorf("Queue should not be active. Is: %v", active)
 	}
 }
+
+func TestSnapshot(t *testing.T) {
+	queue := queue.New(context.Background())
+	el1 := &httpcrawler.RequestMessage{URL: "element 1"}
+	el2 := &httpcrawler.RequestMessage{URL: "element 2", Depth: 1}
+	el3 := &httpcrawler.RequestMessage{URL: "element 3", Depth: 1}
+
+	queue.Push(el1)
+	queue.Push(el2)
+	queue.Push(el3)
+
+	// Poll and acknowledge element 1, poll element 2 without acknowledging it.
+	queue.Ack(queue.Poll().URL)
+	_ = queue.Poll()
+
+	snapshot := queue.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("Snapshot should have 2 elements. Has: %d", len(snapshot))
+	}
+
+	seen := map[string]bool{}
+	for _, v := range snapshot {
+		seen[v.URL] = true
+	}
+
+	if !seen[el2.URL] || !seen[el3.URL] {
+		t.Errorf("Snapshot should contain the active and queued elements. Has: %v", seen)
+	}
+}
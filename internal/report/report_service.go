@@ -8,6 +8,14 @@ import (
 	"github.com/stjudewashere/seonaut/internal/models"
 )
 
+const (
+	// Max number of PageReports returned by GetSlowestPageReports.
+	slowestLimit = 10
+
+	// Max number of duplicate pages listed in a PageReportView.
+	duplicatesLimit = 20
+)
+
 type Cache interface {
 	Set(key string, v interface{}) error
 	Get(key string, v interface{}) error
@@ -40,6 +48,13 @@ type ReportStore interface {
 	CountScheme(int64) *SchemeCount
 	CountByNonCanonical(int64) int
 	GetStatusCodeByDepth(crawlId int64) []StatusCodeByDepth
+	GetResponseTimeRanges(crawlId int64) []ResponseTimeRange
+	FindSlowestPageReports(crawlId int64, limit int) []models.PageReport
+	CountBytesByMediaType(crawlId int64) []MediaTypeBytes
+	GetTLSCertificates(crawlId int64) []models.TLSCertificate
+	FindDuplicatePageReports(pageReport *models.PageReport, crawlId int64, limit int) []DuplicatePage
+	GetSitemapFiles(crawlId int64) []models.SitemapFile
+	GetRobotstxtFiles(crawlId int64) []models.RobotstxtFile
 }
 
 type CanonicalCount struct {
@@ -66,6 +81,29 @@ type StatusCodeByDepth struct {
 	StatusCode500 int
 }
 
+// ResponseTimeRange counts the crawled pages with a total download time
+// in the range of milliseconds described by Range.
+type ResponseTimeRange struct {
+	Range string
+	Pages int
+}
+
+// MediaTypeBytes sums the bytes of the crawled resources of a media type.
+// Size is the uncompressed size and TransferSize the size as it was received.
+type MediaTypeBytes struct {
+	MediaType    string
+	Size         int
+	TransferSize int
+}
+
+// DuplicatePage is a page with the same or a very similar visible text as another page.
+// Exact is true if both texts are identical.
+type DuplicatePage struct {
+	Id    int64
+	URL   string
+	Exact bool
+}
+
 type Service struct {
 	store ReportStore
 	cache Cache
@@ -76,6 +114,7 @@ type PageReportView struct {
 	ErrorTypes []string
 	InLinks    []models.InternalLink
 	Redirects  []models.PageReport
+	Duplicates []DuplicatePage
 	Paginator  models.Paginator
 }
 
@@ -98,6 +137,10 @@ func (s *Service) GetPageReport(rid int, crawlId int64, tab string, page int) *P
 		ErrorTypes: s.store.FindErrorTypesByPage(rid, crawlId),
 	}
 
+	if v.PageReport.ContentHash != "" {
+		v.Duplicates = s.store.FindDuplicatePageReports(&v.PageReport, crawlId, duplicatesLimit)
+	}
+
 	switch tab {
 	case "internal":
 		paginator.TotalPages = s.store.GetNumberOfPagesForLinks(&v.PageReport, crawlId)
@@ -292,6 +335,30 @@ func (s *Service) RemoveCrawlCache(crawl *models.Crawl) {
 	if err := s.cache.Delete(fmt.Sprintf("canonical-%d", crawl.Id)); err != nil {
 		log.Printf("DeleteDashboardCache: Canonical: %v\n", err)
 	}
+
+	if err := s.cache.Delete(fmt.Sprintf("response-time-%d", crawl.Id)); err != nil {
+		log.Printf("DeleteDashboardCache: ResponseTime: %v\n", err)
+	}
+
+	if err := s.cache.Delete(fmt.Sprintf("slowest-%d", crawl.Id)); err != nil {
+		log.Printf("DeleteDashboardCache: Slowest: %v\n", err)
+	}
+
+	if err := s.cache.Delete(fmt.Sprintf("media-bytes-%d", crawl.Id)); err != nil {
+		log.Printf("DeleteDashboardCache: MediaBytes: %v\n", err)
+	}
+
+	if err := s.cache.Delete(fmt.Sprintf("tls-%d", crawl.Id)); err != nil {
+		log.Printf("DeleteDashboardCache: TLS: %v\n", err)
+	}
+
+	if err := s.cache.Delete(fmt.Sprintf("sitemaps-%d", crawl.Id)); err != nil {
+		log.Printf("DeleteDashboardCache: Sitemaps: %v\n", err)
+	}
+
+	if err := s.cache.Delete(fmt.Sprintf("robotstxt-%d", crawl.Id)); err != nil {
+		log.Printf("DeleteDashboardCache: Robotstxt: %v\n", err)
+	}
 }
 
 func (s *Service) GetStatusCodeByDepth(crawlId int64) []StatusCodeByDepth {
@@ -306,3 +373,59 @@ func (s *Service) GetStatusCodeByDepth(crawlId int64) []StatusCodeByDepth {
 
 	return v
 }
+
+// Returns the number of crawled pages in each range of total download time.
+func (s *Service) GetResponseTimeRanges(crawlId int64) []ResponseTimeRange {
+	key := fmt.Sprintf("response-time-%d", crawlId)
+	v := []ResponseTimeRange{}
+	if err := s.cache.Get(key, &v); err != nil {
+		v = s.store.GetResponseTimeRanges(crawlId)
+		if err := s.cache.Set(key, v); err != nil {
+			log.Printf("GetResponseTimeRanges: cacheSet: %v\n", err)
+		}
+	}
+
+	return v
+}
+
+// Returns the crawled PageReports with the longest total download time.
+func (s *Service) GetSlowestPageReports(crawlId int64) []models.PageReport {
+	key := fmt.Sprintf("slowest-%d", crawlId)
+	v := []models.PageReport{}
+	if err := s.cache.Get(key, &v); err != nil {
+		v = s.store.FindSlowestPageReports(crawlId, slowestLimit)
+		if err := s.cache.Set(key, v); err != nil {
+			log.Printf("GetSlowestPageReports: cacheSet: %v\n", err)
+		}
+	}
+
+	return v
+}
+
+// Returns the uncompressed and transferred bytes of the crawled resources by media type.
+func (s *Service) GetBytesByMediaType(crawlId int64) []MediaTypeBytes {
+	key := fmt.Sprintf("media-bytes-%d", crawlId)
+	v := []MediaTypeBytes{}
+	if err := s.cache.Get(key, &v); err != nil {
+		v = s.store.CountBytesByMediaType(crawlId)
+		if err := s.cache.Set(key, v); err != nil {
+			log.Printf("GetBytesByMediaType: cacheSet: %v\n", err)
+		}
+	}
+
+	return v
+}
+
+// Returns the TLS certificates of the hosts found in the crawl.
+func (s *Service) GetTLSCertificates(crawlId int64) []models.TLSCertificate {
+	key := fmt.Sprintf("tls-%d", crawlId)
+	v := []models.TLSCertificate{}
+	if err := s.cache.Get(key, &v); err != nil {
+		v = s.store.GetTLSCertificates(crawlId)
+		if err := s.cache.Set(key, v); err != nil {
+			log.Printf("GetTLSCertificates: cacheSet: %v\n", err)
+		}
+	}
+
+	return v
+}
@@ -1,6 +1,8 @@
 package report_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stjudewashere/seonaut/internal/models"
@@ -129,6 +131,42 @@ func (s *storage) GetStatusCodeByDepth(crawlId int64) []report.StatusCodeByDepth
 	return []report.StatusCodeByDepth{}
 }
 
+func (s *storage) GetResponseTimeRanges(crawlId int64) []report.ResponseTimeRange {
+	return []report.ResponseTimeRange{}
+}
+
+func (s *storage) FindSlowestPageReports(crawlId int64, limit int) []models.PageReport {
+	return []models.PageReport{}
+}
+
+func (s *storage) CountBytesByMediaType(crawlId int64) []report.MediaTypeBytes {
+	return []report.MediaTypeBytes{}
+}
+
+func (s *storage) GetTLSCertificates(crawlId int64) []models.TLSCertificate {
+	return []models.TLSCertificate{}
+}
+
+func (s *storage) FindDuplicatePageReports(pageReport *models.PageReport, crawlId int64, limit int) []report.DuplicatePage {
+	return []report.DuplicatePage{}
+}
+
+func (s *storage) GetSitemapFiles(crawlId int64) []models.SitemapFile {
+	return []models.SitemapFile{
+		{URL: "https://example.com/sitemap.xml", StatusCode: 200, ContentType: "application/xml", URLs: 10},
+		{URL: "https://example.com/sitemap.xml.gz", StatusCode: 200, ContentType: "application/gzip", Compressed: true, DuplicateURLs: 1},
+		{URL: "https://example.com/sitemap-2.xml", StatusCode: 200, ContentType: "text/html", URLs: 50001, FutureLastmod: 2},
+		{URL: "https://example.com/sitemap-3.xml", StatusCode: 404},
+	}
+}
+
+func (s *storage) GetRobotstxtFiles(crawlId int64) []models.RobotstxtFile {
+	return []models.RobotstxtFile{
+		{Host: "example.com", StatusCode: 200, Body: "User-agent: *\nDisallow: /private\n\nUser-agent: Googlebot\nDisallow: /google"},
+		{Host: "www.example.com", StatusCode: 404},
+	}
+}
+
 type cache struct{}
 
 func (c *cache) Set(key string, v interface{}) error {
@@ -207,3 +245,86 @@ func TestGetPageReport(t *testing.T) {
 		t.Errorf("v.Redirects: %d != 1", len(vr.Redirects))
 	}
 }
+
+// missCache is a cache that doesn't store any value, so the service always uses the storage.
+type missCache struct {
+	cache
+}
+
+func (c *missCache) Get(key string, v interface{}) error {
+	return errors.New("cache miss")
+}
+
+func TestGetSitemapFiles(t *testing.T) {
+	service := report.NewService(&storage{}, &missCache{})
+
+	expected := [][]string{
+		{},
+		{"SITEMAP_DUPLICATE_URLS"},
+		{"SITEMAP_CONTENT_TYPE", "SITEMAP_TOO_MANY_URLS", "SITEMAP_FUTURE_LASTMOD"},
+		{"SITEMAP_STATUS_CODE"},
+	}
+
+	files := service.GetSitemapFiles(crawlId)
+	if len(files) != len(expected) {
+		t.Fatalf("GetSitemapFiles: %d != %d", len(files), len(expected))
+	}
+
+	for i, f := range files {
+		if strings.Join(f.Issues, ",") != strings.Join(expected[i], ",") {
+			t.Errorf("%s issues: %v != %v", f.File.URL, f.Issues, expected[i])
+		}
+	}
+}
+
+func TestTestRobotstxt(t *testing.T) {
+	service := report.NewService(&storage{}, &missCache{})
+
+	urls := []string{
+		"https://example.com/private",
+		"https://example.com/google",
+		"",
+		"https://www.example.com/private",
+		"https://blog.example.com/private",
+		"/private",
+	}
+
+	table := []struct {
+		agent    string
+		expected []report.RobotstxtTest
+	}{
+		{
+			"",
+			[]report.RobotstxtTest{
+				{URL: "https://example.com/private", Found: true, Allowed: false},
+				{URL: "https://example.com/google", Found: true, Allowed: true},
+				{URL: "https://www.example.com/private", Found: true, Allowed: true},
+				{URL: "https://blog.example.com/private", Allowed: true},
+				{URL: "/private", Invalid: true, Allowed: true},
+			},
+		},
+		{
+			"Googlebot",
+			[]report.RobotstxtTest{
+				{URL: "https://example.com/private", Found: true, Allowed: true},
+				{URL: "https://example.com/google", Found: true, Allowed: false},
+				{URL: "https://www.example.com/private", Found: true, Allowed: true},
+				{URL: "https://blog.example.com/private", Allowed: true},
+				{URL: "/private", Invalid: true, Allowed: true},
+			},
+		},
+	}
+
+	for _, tc := range table {
+		tests := service.TestRobotstxt(crawlId, tc.agent, urls)
+		if len(tests) != len(tc.expected) {
+			t.Fatalf("TestRobotstxt %s: %d != %d", tc.agent, len(tests), len(tc.expected))
+		}
+
+		for i, test := range tests {
+			if test != tc.expected[i] {
+				t.Errorf("TestRobotstxt %s: %+v != %+v", tc.agent, test, tc.expected[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"github.com/temoto/robotstxt"
+)
+
+// Max number of URLs tested at once against the robots.txt files.
+const robotstxtTestLimit = 100
+
+// RobotstxtTest is the result of testing a URL against the robots.txt files used in a crawl.
+// Found is false if there is no robots.txt file of the URL's host in the crawl, and Invalid
+// is true if the URL couldn't be parsed.
+type RobotstxtTest struct {
+	URL     string
+	Invalid bool
+	Found   bool
+	Allowed bool
+}
+
+// Returns the robots.txt files used in the crawl.
+func (s *Service) GetRobotstxtFiles(crawlId int64) []models.RobotstxtFile {
+	key := fmt.Sprintf("robotstxt-%d", crawlId)
+	v := []models.RobotstxtFile{}
+	if err := s.cache.Get(key, &v); err != nil {
+		v = s.store.GetRobotstxtFiles(crawlId)
+		if err := s.cache.Set(key, v); err != nil {
+			log.Printf("GetRobotstxtFiles: cacheSet: %v\n", err)
+		}
+	}
+
+	return v
+}
+
+// Tests the URLs against the robots.txt files used in the crawl for the user agent token.
+// The files are checked the same way the crawler does, so the rules of files that were not
+// found or couldn't be fetched allow all the URLs.
+func (s *Service) TestRobotstxt(crawlId int64, agent string, urls []string) []RobotstxtTest {
+	if agent == "" {
+		agent = "*"
+	}
+
+	robots := make(map[string]*robotstxt.RobotsData)
+	for _, f := range s.GetRobotstxtFiles(crawlId) {
+		robots[f.Host] = nil
+		if f.StatusCode != http.StatusOK {
+			continue
+		}
+
+		robot, err := robotstxt.FromString(f.Body)
+		if err == nil {
+			robots[f.Host] = robot
+		}
+	}
+
+	tests := []RobotstxtTest{}
+	for _, v := range urls {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		if len(tests) >= robotstxtTestLimit {
+			break
+		}
+
+		tests = append(tests, testRobotstxt(robots, agent, v))
+	}
+
+	return tests
+}
+
+// Tests a URL against the robots.txt rules of its host for the user agent token.
+// The robots map has a nil value for hosts whose file doesn't have any rules.
+func testRobotstxt(robots map[string]*robotstxt.RobotsData, agent, v string) RobotstxtTest {
+	test := RobotstxtTest{URL: v, Allowed: true}
+
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		test.Invalid = true
+		return test
+	}
+
+	robot, ok := robots[u.Host]
+	test.Found = ok
+	if robot == nil {
+		return test
+	}
+
+	path := u.EscapedPath()
+	if u.RawQuery != "" {
+		path += "?" + u.Query().Encode()
+	}
+
+	test.Allowed = robot.TestAgent(path, agent)
+
+	return test
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"fmt"
+	"log"
+	"mime"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Media types allowed for sitemap files. Compressed sitemaps can also be served as gzip files.
+var (
+	sitemapMediaTypes           = map[string]bool{"application/xml": true, "text/xml": true}
+	compressedSitemapMediaTypes = map[string]bool{"application/gzip": true, "application/x-gzip": true}
+)
+
+// SitemapFileView is a sitemap file fetched in a crawl with the keys of its issues.
+type SitemapFileView struct {
+	File   models.SitemapFile
+	Issues []string
+}
+
+// Returns the sitemap files fetched in the crawl with their issues.
+func (s *Service) GetSitemapFiles(crawlId int64) []SitemapFileView {
+	key := fmt.Sprintf("sitemaps-%d", crawlId)
+	v := []SitemapFileView{}
+	if err := s.cache.Get(key, &v); err != nil {
+		for _, f := range s.store.GetSitemapFiles(crawlId) {
+			v = append(v, SitemapFileView{File: f, Issues: sitemapIssues(&f)})
+		}
+
+		if err := s.cache.Set(key, v); err != nil {
+			log.Printf("GetSitemapFiles: cacheSet: %v\n", err)
+		}
+	}
+
+	return v
+}
+
+// Returns the keys of the issues of a sitemap file.
+func sitemapIssues(f *models.SitemapFile) []string {
+	issues := []string{}
+
+	if f.FetchError != "" {
+		return append(issues, "SITEMAP_FETCH_ERROR")
+	}
+
+	if f.StatusCode < 200 || f.StatusCode >= 300 {
+		return append(issues, "SITEMAP_STATUS_CODE")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(f.ContentType)
+	if !sitemapMediaTypes[mediaType] && !(f.Compressed && compressedSitemapMediaTypes[mediaType]) {
+		issues = append(issues, "SITEMAP_CONTENT_TYPE")
+	}
+
+	if f.ParseError != "" {
+		issues = append(issues, "SITEMAP_PARSE_ERROR")
+	}
+
+	if f.URLs > models.SitemapMaxURLs {
+		issues = append(issues, "SITEMAP_TOO_MANY_URLS")
+	}
+
+	if f.Size > models.SitemapMaxSize {
+		issues = append(issues, "SITEMAP_TOO_LARGE")
+	}
+
+	if f.InvalidLastmod > 0 {
+		issues = append(issues, "SITEMAP_INVALID_LASTMOD")
+	}
+
+	if f.FutureLastmod > 0 {
+		issues = append(issues, "SITEMAP_FUTURE_LASTMOD")
+	}
+
+	if f.ExternalURLs > 0 {
+		issues = append(issues, "SITEMAP_EXTERNAL_URLS")
+	}
+
+	if f.DuplicateURLs > 0 {
+		issues = append(issues, "SITEMAP_DUPLICATE_URLS")
+	}
+
+	return issues
+}
@@ -66,4 +66,29 @@ const (
 	ErrorMultipleTitleTags                       // Pages with more than one title tag in the header
 	ErrorMultipleDescriptionTags                 // Pages with more than one meta description tag
 	ErrorDepth                                   // Pages with high depth
+	ErrorFetch                                   // URLs that could not be fetched
+	ErrorSlowTTFB                                // Pages with a slow time to first byte
+	ErrorSlowDownload                            // Pages that are slow to download
+	ErrorUncompressed                            // Text resources served without compression
+	ErrorNoHTTP2                                 // HTTPS pages served over HTTP/1.x
+	ErrorCertificateExpiring                     // Pages with an expired or expiring TLS certificate
+	ErrorCertificateHostnameMismatch             // Pages with a TLS certificate not valid for their host
+	ErrorCertificateIncompleteChain              // Pages with an incomplete or untrusted certificate chain
+	ErrorDeprecatedTLS                           // Pages served with a TLS version older than 1.2
+	ErrorDuplicatedContent                       // Pages with the same visible text
+	ErrorNearDuplicatedContent                   // Pages with a very similar visible text
+	ErrorBrokenExternalLinks                     // Pages linking to external URLs with an error status code
+	ErrorRedirectedExternalLinks                 // Pages linking to external URLs that redirect
+	ErrorBrokenImages                            // Pages embedding images with an error status code
+	ErrorBrokenScripts                           // Pages loading scripts with an error status code
+	ErrorBrokenStyles                            // Pages loading stylesheets with an error status code
+	ErrorBrokenAudios                            // Pages embedding audios with an error status code
+	ErrorBrokenVideos                            // Pages embedding videos with an error status code
+	ErrorRedirectedImages                        // Pages embedding images that redirect
+	ErrorRedirectedScripts                       // Pages loading scripts that redirect
+	ErrorRedirectedStyles                        // Pages loading stylesheets that redirect
+	ErrorRedirectedAudios                        // Pages embedding audios that redirect
+	ErrorRedirectedVideos                        // Pages embedding videos that redirect
+	ErrorNotInSitemap                            // Indexable pages missing from the sitemaps
+	ErrorSitemapOnly                             // Sitemap URLs without internal inlinks
 )
@@ -0,0 +1,107 @@
+package reporters
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/report_manager"
+	"github.com/stjudewashere/seonaut/internal/report_manager/reporter_errors"
+)
+
+const (
+	// Min size in bytes of the text resources that should be compressed.
+	minCompressSize = 1024
+)
+
+// Media types of the text resources that benefit from being compressed.
+var compressibleMediaTypes = map[string]bool{
+	"text/html":              true,
+	"text/css":               true,
+	"text/javascript":        true,
+	"text/plain":             true,
+	"text/xml":               true,
+	"application/javascript": true,
+	"application/json":       true,
+	"application/xml":        true,
+	"image/svg+xml":          true,
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that checks
+// if the time to first byte of a crawled page is greater than the threshold in milliseconds.
+func NewSlowTTFBReporter(threshold int) *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !pageReport.Crawled {
+			return false
+		}
+
+		return pageReport.Timing.TTFB > threshold
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorSlowTTFB,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that checks
+// if the total download time of a crawled page is greater than the threshold in milliseconds.
+func NewSlowDownloadReporter(threshold int) *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !pageReport.Crawled {
+			return false
+		}
+
+		return pageReport.Timing.Download > threshold
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorSlowDownload,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that checks
+// if a text resource, such as an HTML, CSS or JavaScript file, was served without compression.
+// Small resources are not reported, as compressing them barely reduces their size.
+func NewUncompressedReporter() *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !pageReport.Crawled || pageReport.Transfer.Protocol == "" {
+			return false
+		}
+
+		if pageReport.StatusCode < 200 || pageReport.StatusCode >= 300 {
+			return false
+		}
+
+		if !compressibleMediaTypes[pageReport.MediaType] || pageReport.Size < minCompressSize {
+			return false
+		}
+
+		return pageReport.Transfer.ContentEncoding == "" || pageReport.Transfer.ContentEncoding == "identity"
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorUncompressed,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that checks
+// if an HTTPS URL was served over HTTP/1.x because the server doesn't support HTTP/2.
+func NewNoHTTP2Reporter() *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !pageReport.Crawled || !strings.HasPrefix(pageReport.URL, "https://") {
+			return false
+		}
+
+		return strings.HasPrefix(pageReport.Transfer.Protocol, "HTTP/1.")
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorNoHTTP2,
+		Callback:  c,
+	}
+}
@@ -0,0 +1,113 @@
+package reporters_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/report_manager/reporter_errors"
+	"github.com/stjudewashere/seonaut/internal/report_manager/reporters"
+
+	"golang.org/x/net/html"
+)
+
+// Test the SlowTTFB reporter with page reports below and above the threshold.
+// Only the crawled page with a slow TTFB should be reported.
+func TestSlowTTFBReporter(t *testing.T) {
+	table := []struct {
+		pageReport *models.PageReport
+		want       bool
+	}{
+		{&models.PageReport{Crawled: true, Timing: models.ResponseTiming{TTFB: 200}}, false},
+		{&models.PageReport{Crawled: true, Timing: models.ResponseTiming{TTFB: 800}}, true},
+		{&models.PageReport{Crawled: false, Timing: models.ResponseTiming{TTFB: 800}}, false},
+	}
+
+	reporter := reporters.NewSlowTTFBReporter(600)
+	if reporter.ErrorType != reporter_errors.ErrorSlowTTFB {
+		t.Errorf("TestSlowTTFBReporter: error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(v.pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("TestSlowTTFBReporter: TTFB %d reportsIssue should be %v", v.pageReport.Timing.TTFB, v.want)
+		}
+	}
+}
+
+// Test the SlowDownload reporter with page reports below and above the threshold.
+// Only the crawled page with a slow download should be reported.
+func TestSlowDownloadReporter(t *testing.T) {
+	table := []struct {
+		pageReport *models.PageReport
+		want       bool
+	}{
+		{&models.PageReport{Crawled: true, Timing: models.ResponseTiming{Download: 1000}}, false},
+		{&models.PageReport{Crawled: true, Timing: models.ResponseTiming{Download: 3000}}, true},
+		{&models.PageReport{Crawled: false, Timing: models.ResponseTiming{Download: 3000}}, false},
+	}
+
+	reporter := reporters.NewSlowDownloadReporter(2000)
+	if reporter.ErrorType != reporter_errors.ErrorSlowDownload {
+		t.Errorf("TestSlowDownloadReporter: error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(v.pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("TestSlowDownloadReporter: download %d reportsIssue should be %v", v.pageReport.Timing.Download, v.want)
+		}
+	}
+}
+
+// Test the Uncompressed reporter with compressed, small, binary and uncompressed resources.
+// Only the large uncompressed text resource should be reported.
+func TestUncompressedReporter(t *testing.T) {
+	table := []struct {
+		pageReport *models.PageReport
+		want       bool
+	}{
+		{&models.PageReport{Crawled: true, StatusCode: 200, MediaType: "text/html", Size: 50000, Transfer: models.ResponseTransfer{Protocol: "HTTP/2.0", ContentEncoding: "gzip"}}, false},
+		{&models.PageReport{Crawled: true, StatusCode: 200, MediaType: "text/css", Size: 500, Transfer: models.ResponseTransfer{Protocol: "HTTP/2.0"}}, false},
+		{&models.PageReport{Crawled: true, StatusCode: 200, MediaType: "image/png", Size: 50000, Transfer: models.ResponseTransfer{Protocol: "HTTP/2.0"}}, false},
+		{&models.PageReport{Crawled: true, StatusCode: 200, MediaType: "text/javascript", Size: 50000, Transfer: models.ResponseTransfer{Protocol: "HTTP/2.0"}}, true},
+	}
+
+	reporter := reporters.NewUncompressedReporter()
+	if reporter.ErrorType != reporter_errors.ErrorUncompressed {
+		t.Errorf("TestUncompressedReporter: error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(v.pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("TestUncompressedReporter: %s %v reportsIssue should be %v", v.pageReport.MediaType, v.pageReport.Transfer, v.want)
+		}
+	}
+}
+
+// Test the NoHTTP2 reporter with HTTPS and HTTP URLs served over different protocols.
+// Only the HTTPS URL served over HTTP/1.1 should be reported.
+func TestNoHTTP2Reporter(t *testing.T) {
+	table := []struct {
+		pageReport *models.PageReport
+		want       bool
+	}{
+		{&models.PageReport{Crawled: true, URL: "https://example.com", Transfer: models.ResponseTransfer{Protocol: "HTTP/2.0"}}, false},
+		{&models.PageReport{Crawled: true, URL: "http://example.com", Transfer: models.ResponseTransfer{Protocol: "HTTP/1.1"}}, false},
+		{&models.PageReport{Crawled: true, URL: "https://example.com", Transfer: models.ResponseTransfer{Protocol: "HTTP/1.1"}}, true},
+	}
+
+	reporter := reporters.NewNoHTTP2Reporter()
+	if reporter.ErrorType != reporter_errors.ErrorNoHTTP2 {
+		t.Errorf("TestNoHTTP2Reporter: error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(v.pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("TestNoHTTP2Reporter: %s %s reportsIssue should be %v", v.pageReport.URL, v.pageReport.Transfer.Protocol, v.want)
+		}
+	}
+}
@@ -4,13 +4,51 @@ import (
 	"github.com/stjudewashere/seonaut/internal/report_manager"
 )
 
+const (
+	// Default threshold in milliseconds of the time to first byte of a page.
+	defaultSlowTTFB = 600
+
+	// Default threshold in milliseconds of the total download time of a page.
+	defaultSlowDownload = 2000
+
+	// Default number of days before the expiration of a TLS certificate in which it is reported.
+	defaultCertExpiryDays = 30
+)
+
+// Config stores the thresholds of the reporters.
+// It is loaded from the config package. Zero values are replaced by the defaults.
+type Config struct {
+	SlowTTFB       int `mapstructure:"slow_ttfb"`        // Milliseconds
+	SlowDownload   int `mapstructure:"slow_download"`    // Milliseconds
+	CertExpiryDays int `mapstructure:"cert_expiry_days"` // Days before a certificate expires
+}
+
 // Returns an slice with all available report_manager.PageIssueReporters.
-func GetAllReporters() []*report_manager.PageIssueReporter {
+// The config sets the thresholds of the reporters, it can be nil to use the defaults.
+func GetAllReporters(config *Config) []*report_manager.PageIssueReporter {
+	slowTTFB := defaultSlowTTFB
+	slowDownload := defaultSlowDownload
+	certExpiryDays := defaultCertExpiryDays
+	if config != nil {
+		if config.SlowTTFB > 0 {
+			slowTTFB = config.SlowTTFB
+		}
+
+		if config.SlowDownload > 0 {
+			slowDownload = config.SlowDownload
+		}
+
+		if config.CertExpiryDays > 0 {
+			certExpiryDays = config.CertExpiryDays
+		}
+	}
+
 	return []*report_manager.PageIssueReporter{
 		// Add status code issue reporters
 		NewStatus30xReporter(),
 		NewStatus40xReporter(),
 		NewStatus50xReporter(),
+		NewFetchErrorReporter(),
 
 		// Add title issue reporters
 		NewEmptyTitleReporter(),
@@ -69,5 +107,15 @@ func GetAllReporters() []*report_manager.PageIssueReporter {
 		NewMissingHSTSHeaderReporter(),
 		NewMissingCSPReporter(),
 		NewMissingContentTypeOptionsReporter(),
+		NewCertificateExpiringReporter(certExpiryDays),
+		NewCertificateHostnameMismatchReporter(),
+		NewCertificateIncompleteChainReporter(),
+		NewDeprecatedTLSReporter(),
+
+		// Add performance issue reporters
+		NewSlowTTFBReporter(slowTTFB),
+		NewSlowDownloadReporter(slowDownload),
+		NewUncompressedReporter(),
+		NewNoHTTP2Reporter(),
 	}
 }
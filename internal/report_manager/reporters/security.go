@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/report_manager"
@@ -18,6 +19,11 @@ import (
 // header does not exist or is not valid.
 func NewMissingHSTSHeaderReporter() *report_manager.PageIssueReporter {
 	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		// There are no headers if the URL could not be fetched.
+		if pageReport.FetchError != "" {
+			return false
+		}
+
 		hstsHeader := header.Get("Strict-Transport-Security")
 		if hstsHeader == "" {
 			return true
@@ -87,3 +93,79 @@ func NewMissingContentTypeOptionsReporter() *report_manager.PageIssueReporter {
 		Callback:  c,
 	}
 }
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports
+// if the certificate of the page's host has expired or expires within the given days.
+func NewCertificateExpiringReporter(days int) *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.TLS == nil {
+			return false
+		}
+
+		return time.Until(pageReport.TLS.NotAfter) < time.Duration(days)*24*time.Hour
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorCertificateExpiring,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports
+// if the certificate of the page's host is not valid for the host name.
+func NewCertificateHostnameMismatchReporter() *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.TLS == nil {
+			return false
+		}
+
+		return !pageReport.TLS.HostnameMatch
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorCertificateHostnameMismatch,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports
+// if the certificate chain of the page's host doesn't verify up to a trusted root, usually
+// because the server doesn't send the intermediate certificates. Expired certificates
+// are not reported, as they are already reported by the CertificateExpiring reporter.
+func NewCertificateIncompleteChainReporter() *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.TLS == nil || pageReport.TLS.ValidChain {
+			return false
+		}
+
+		return time.Now().Before(pageReport.TLS.NotAfter)
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorCertificateIncompleteChain,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports
+// if the page's host only supports a TLS version older than TLS 1.2.
+func NewDeprecatedTLSReporter() *report_manager.PageIssueReporter {
+	deprecated := map[string]bool{
+		"SSLv3":   true,
+		"TLS 1.0": true,
+		"TLS 1.1": true,
+	}
+
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.TLS == nil {
+			return false
+		}
+
+		return deprecated[pageReport.TLS.Version]
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorDeprecatedTLS,
+		Callback:  c,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/report_manager/reporter_errors"
@@ -200,3 +201,110 @@ func TestMissingContentTypeOptionsIssues(t *testing.T) {
 		t.Errorf("reportsIssue should be true")
 	}
 }
+
+// Test the CertificateExpiring reporter with certificates that expire at different times.
+// The expired certificate and the one expiring within the days should be reported.
+func TestCertificateExpiringReporter(t *testing.T) {
+	table := []struct {
+		tls  *models.TLSCertificate
+		want bool
+	}{
+		{nil, false},
+		{&models.TLSCertificate{NotAfter: time.Now().Add(90 * 24 * time.Hour)}, false},
+		{&models.TLSCertificate{NotAfter: time.Now().Add(10 * 24 * time.Hour)}, true},
+		{&models.TLSCertificate{NotAfter: time.Now().Add(-24 * time.Hour)}, true},
+	}
+
+	reporter := reporters.NewCertificateExpiringReporter(30)
+	if reporter.ErrorType != reporter_errors.ErrorCertificateExpiring {
+		t.Errorf("error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(&models.PageReport{Crawled: true, TLS: v.tls}, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("%+v reportsIssue should be %v", v.tls, v.want)
+		}
+	}
+}
+
+// Test the CertificateHostnameMismatch reporter with matching and mismatching certificates.
+// Only the mismatching certificate should be reported.
+func TestCertificateHostnameMismatchReporter(t *testing.T) {
+	table := []struct {
+		tls  *models.TLSCertificate
+		want bool
+	}{
+		{nil, false},
+		{&models.TLSCertificate{HostnameMatch: true}, false},
+		{&models.TLSCertificate{HostnameMatch: false}, true},
+	}
+
+	reporter := reporters.NewCertificateHostnameMismatchReporter()
+	if reporter.ErrorType != reporter_errors.ErrorCertificateHostnameMismatch {
+		t.Errorf("error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(&models.PageReport{Crawled: true, TLS: v.tls}, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("%+v reportsIssue should be %v", v.tls, v.want)
+		}
+	}
+}
+
+// Test the CertificateIncompleteChain reporter with valid, untrusted and expired certificates.
+// Only the untrusted certificate that has not expired should be reported.
+func TestCertificateIncompleteChainReporter(t *testing.T) {
+	valid := time.Now().Add(90 * 24 * time.Hour)
+	expired := time.Now().Add(-24 * time.Hour)
+
+	table := []struct {
+		tls  *models.TLSCertificate
+		want bool
+	}{
+		{nil, false},
+		{&models.TLSCertificate{ValidChain: true, NotAfter: valid}, false},
+		{&models.TLSCertificate{ValidChain: false, NotAfter: valid}, true},
+		{&models.TLSCertificate{ValidChain: false, NotAfter: expired}, false},
+	}
+
+	reporter := reporters.NewCertificateIncompleteChainReporter()
+	if reporter.ErrorType != reporter_errors.ErrorCertificateIncompleteChain {
+		t.Errorf("error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(&models.PageReport{Crawled: true, TLS: v.tls}, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("%+v reportsIssue should be %v", v.tls, v.want)
+		}
+	}
+}
+
+// Test the DeprecatedTLS reporter with current and deprecated TLS versions.
+// Only the deprecated versions should be reported.
+func TestDeprecatedTLSReporter(t *testing.T) {
+	table := []struct {
+		tls  *models.TLSCertificate
+		want bool
+	}{
+		{nil, false},
+		{&models.TLSCertificate{Version: "TLS 1.3"}, false},
+		{&models.TLSCertificate{Version: "TLS 1.2"}, false},
+		{&models.TLSCertificate{Version: "TLS 1.1"}, true},
+		{&models.TLSCertificate{Version: "TLS 1.0"}, true},
+	}
+
+	reporter := reporters.NewDeprecatedTLSReporter()
+	if reporter.ErrorType != reporter_errors.ErrorDeprecatedTLS {
+		t.Errorf("error type is not correct")
+	}
+
+	for _, v := range table {
+		reportsIssue := reporter.Callback(&models.PageReport{Crawled: true, TLS: v.tls}, &html.Node{}, &http.Header{})
+		if reportsIssue != v.want {
+			t.Errorf("%+v reportsIssue should be %v", v.tls, v.want)
+		}
+	}
+}
@@ -60,3 +60,16 @@ func NewStatus50xReporter() *report_manager.PageIssueReporter {
 		Callback:  c,
 	}
 }
+
+// Returns a new report_manager.PageIssueReporter with a callback function that
+// checks if the URL could not be fetched because of a network error.
+func NewFetchErrorReporter() *report_manager.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		return pageReport.FetchError != ""
+	}
+
+	return &report_manager.PageIssueReporter{
+		ErrorType: reporter_errors.ErrorFetch,
+		Callback:  c,
+	}
+}
@@ -130,3 +130,43 @@ func TestStatus50xIssues(t *testing.T) {
 		t.Errorf("TestStatus50xIssues: reportsIssue should be true")
 	}
 }
+
+// Test the FetchError reporter with a PageReport that has been fetched.
+// The reporter should not report the issue.
+func TestFetchErrorNoIssues(t *testing.T) {
+	pageReport := &models.PageReport{
+		Crawled:    true,
+		StatusCode: 200,
+	}
+
+	reporter := reporters.NewFetchErrorReporter()
+	if reporter.ErrorType != reporter_errors.ErrorFetch {
+		t.Errorf("TestNoIssues: error type is not correct")
+	}
+
+	reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+
+	if reportsIssue == true {
+		t.Errorf("TestFetchErrorNoIssues: reportsIssue should be false")
+	}
+}
+
+// Test the FetchError reporter with a PageReport that could not be fetched.
+// The reporter should report the issue.
+func TestFetchErrorIssues(t *testing.T) {
+	pageReport := &models.PageReport{
+		Crawled:    true,
+		FetchError: "timeout",
+	}
+
+	reporter := reporters.NewFetchErrorReporter()
+	if reporter.ErrorType != reporter_errors.ErrorFetch {
+		t.Errorf("TestNoIssues: error type is not correct")
+	}
+
+	reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+
+	if reportsIssue == false {
+		t.Errorf("TestFetchErrorIssues: reportsIssue should be true")
+	}
+}
@@ -0,0 +1,98 @@
+package sql_reporters
+
+import (
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/report_manager"
+	"github.com/stjudewashere/seonaut/internal/report_manager/reporter_errors"
+	"github.com/stjudewashere/seonaut/internal/simhash"
+)
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages with identical
+// visible text. It considers factors such as the HTTP status code, media type and whether they are canonical or not.
+func (sr *SqlReporter) DuplicatedContentReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	query := `
+		SELECT
+			y.id
+		FROM pagereports y
+		INNER JOIN (
+			SELECT
+				content_hash,
+				count(*) AS c
+			FROM pagereports
+			WHERE crawl_id = ? AND media_type = "text/html" AND status_code >= 200
+			AND status_code < 300 AND (canonical = "" OR canonical = url) AND crawled = 1
+			AND content_hash != ""
+			GROUP BY content_hash
+			HAVING c > 1
+		) d
+		ON d.content_hash = y.content_hash
+		WHERE media_type = "text/html" AND crawl_id = ?
+		AND status_code >= 200 AND status_code < 300 AND (canonical = "" OR canonical = url) AND crawled = 1`
+
+	return &report_manager.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id, c.Id),
+		ErrorType: reporter_errors.ErrorDuplicatedContent,
+	}
+}
+
+// Creates a MultipageIssueReporter object that sends the pages with a visible text similar to the
+// text of another page. The SimHash fingerprints of the pages are compared in memory, and pages
+// that are exact duplicates of each other are left to the DuplicatedContentReporter.
+func (sr *SqlReporter) NearDuplicatedContentReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	query := `
+		SELECT
+			id,
+			content_hash,
+			simhash
+		FROM pagereports
+		WHERE crawl_id = ? AND media_type = "text/html" AND status_code >= 200
+		AND status_code < 300 AND (canonical = "" OR canonical = url) AND crawled = 1
+		AND content_hash != ""`
+
+	prStream := make(chan int64)
+
+	go func() {
+		defer close(prStream)
+
+		rows, err := sr.db.Query(query, c.Id)
+		if err != nil {
+			log.Printf("Error executing query: %s, Args: %v, Error: %v", query, c.Id, err)
+			return
+		}
+		defer rows.Close()
+
+		hashes := make(map[int64]string)
+		fingerprints := make(map[int64]uint64)
+		for rows.Next() {
+			var (
+				pid         int64
+				contentHash string
+				fingerprint uint64
+			)
+
+			if err := rows.Scan(&pid, &contentHash, &fingerprint); err != nil {
+				log.Printf("Error scanning results for query: %s, Args: %v, Error: %v", query, c.Id, err)
+				continue
+			}
+
+			hashes[pid] = contentHash
+			fingerprints[pid] = fingerprint
+		}
+
+		for pid, neighbors := range simhash.Neighbors(fingerprints, simhash.NearDuplicateDistance) {
+			for _, n := range neighbors {
+				if hashes[n] != hashes[pid] {
+					prStream <- pid
+					break
+				}
+			}
+		}
+	}()
+
+	return &report_manager.MultipageIssueReporter{
+		Pstream:   prStream,
+		ErrorType: reporter_errors.ErrorNearDuplicatedContent,
+	}
+}
@@ -53,3 +53,41 @@ func (sr *SqlReporter) FollowNoFollowReporter(c *models.Crawl) *report_manager.M
 		ErrorType: reporter_errors.ErrorIncomingFollowNofollow,
 	}
 }
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// linking to external URLs that respond with an error status code or could not be fetched.
+func (sr *SqlReporter) BrokenExternalLinksReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	query := `
+		SELECT
+			DISTINCT external_links.pagereport_id
+		FROM external_links
+		INNER JOIN external_link_statuses
+			ON external_link_statuses.crawl_id = external_links.crawl_id
+			AND external_link_statuses.url_hash = external_links.url_hash
+		WHERE external_links.crawl_id = ?
+		AND (external_link_statuses.status_code >= 400 OR external_link_statuses.fetch_error != "")`
+
+	return &report_manager.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: reporter_errors.ErrorBrokenExternalLinks,
+	}
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// linking to external URLs that redirect to another URL.
+func (sr *SqlReporter) RedirectedExternalLinksReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	query := `
+		SELECT
+			DISTINCT external_links.pagereport_id
+		FROM external_links
+		INNER JOIN external_link_statuses
+			ON external_link_statuses.crawl_id = external_links.crawl_id
+			AND external_link_statuses.url_hash = external_links.url_hash
+		WHERE external_links.crawl_id = ?
+		AND external_link_statuses.status_code >= 300 AND external_link_statuses.status_code < 400`
+
+	return &report_manager.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: reporter_errors.ErrorRedirectedExternalLinks,
+	}
+}
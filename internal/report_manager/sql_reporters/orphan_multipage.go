@@ -7,14 +7,16 @@ import (
 )
 
 // Creates a MultipageIssueReporter object that contains the SQL query to check for orphan pages.
-// Pages with no incoming links are considered orphan pages.
+// Pages with no incoming links are considered orphan pages. The crawled pages included in the
+// sitemap are reported by the SitemapOnlyReporter instead.
 func (sr *SqlReporter) OrphanPagesReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
 	query := `
 		SELECT
 			pagereports.id
 		FROM pagereports
 		LEFT JOIN links ON pagereports.url_hash = links.url_hash and pagereports.crawl_id = links.crawl_id
-		WHERE pagereports.media_type = "text/html" AND links.url IS NULL AND pagereports.crawl_id = ?`
+		WHERE pagereports.media_type = "text/html" AND links.url IS NULL
+			AND (pagereports.in_sitemap = 0 OR pagereports.crawled = 0) AND pagereports.crawl_id = ?`
 
 	return &report_manager.MultipageIssueReporter{
 		Pstream:   sr.pageReportsQuery(query, c.Id),
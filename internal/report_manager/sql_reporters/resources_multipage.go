@@ -0,0 +1,95 @@
+package sql_reporters
+
+import (
+	"fmt"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/report_manager"
+	"github.com/stjudewashere/seonaut/internal/report_manager/reporter_errors"
+)
+
+const (
+	// Condition of the resources' page reports with an error status code or a fetch error.
+	brokenResource = `(pagereports.status_code >= 400 OR pagereports.fetch_error != "")`
+
+	// Condition of the resources' page reports that redirect to another URL.
+	redirectedResource = `pagereports.status_code >= 300 AND pagereports.status_code < 400`
+)
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// embedding images that respond with an error status code or could not be fetched.
+func (sr *SqlReporter) BrokenImagesReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "images", brokenResource, reporter_errors.ErrorBrokenImages)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// loading scripts that respond with an error status code or could not be fetched.
+func (sr *SqlReporter) BrokenScriptsReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "scripts", brokenResource, reporter_errors.ErrorBrokenScripts)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// loading stylesheets that respond with an error status code or could not be fetched.
+func (sr *SqlReporter) BrokenStylesReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "styles", brokenResource, reporter_errors.ErrorBrokenStyles)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// embedding audios that respond with an error status code or could not be fetched.
+func (sr *SqlReporter) BrokenAudiosReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "audios", brokenResource, reporter_errors.ErrorBrokenAudios)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// embedding videos that respond with an error status code or could not be fetched.
+func (sr *SqlReporter) BrokenVideosReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "videos", brokenResource, reporter_errors.ErrorBrokenVideos)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// embedding images that redirect to another URL.
+func (sr *SqlReporter) RedirectedImagesReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "images", redirectedResource, reporter_errors.ErrorRedirectedImages)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// loading scripts that redirect to another URL.
+func (sr *SqlReporter) RedirectedScriptsReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "scripts", redirectedResource, reporter_errors.ErrorRedirectedScripts)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// loading stylesheets that redirect to another URL.
+func (sr *SqlReporter) RedirectedStylesReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "styles", redirectedResource, reporter_errors.ErrorRedirectedStyles)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// embedding audios that redirect to another URL.
+func (sr *SqlReporter) RedirectedAudiosReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "audios", redirectedResource, reporter_errors.ErrorRedirectedAudios)
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// embedding videos that redirect to another URL.
+func (sr *SqlReporter) RedirectedVideosReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	return sr.resourceReporter(c, "videos", redirectedResource, reporter_errors.ErrorRedirectedVideos)
+}
+
+// Returns a MultipageIssueReporter with the pages that include a resource of the table
+// whose own page report matches the condition. Resources that have not been crawled are ignored.
+func (sr *SqlReporter) resourceReporter(c *models.Crawl, table, condition string, errorType int) *report_manager.MultipageIssueReporter {
+	query := fmt.Sprintf(`
+		SELECT
+			DISTINCT %[1]s.pagereport_id
+		FROM %[1]s
+		INNER JOIN pagereports
+			ON pagereports.crawl_id = %[1]s.crawl_id
+			AND pagereports.url_hash = %[1]s.url_hash
+		WHERE %[1]s.crawl_id = ? AND pagereports.crawled = 1 AND %[2]s`, table, condition)
+
+	return &report_manager.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errorType,
+	}
+}
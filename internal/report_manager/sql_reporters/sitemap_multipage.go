@@ -0,0 +1,48 @@
+package sql_reporters
+
+import (
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/report_manager"
+	"github.com/stjudewashere/seonaut/internal/report_manager/reporter_errors"
+)
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for indexable pages
+// that are not included in any sitemap. Only 200 canonical HTML pages are considered, and the check
+// is only done if the sitemaps were fetched during the crawl.
+func (sr *SqlReporter) NotInSitemapReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	query := `
+		SELECT
+			id
+		FROM pagereports
+		WHERE crawl_id = ? AND media_type = "text/html" AND in_sitemap = 0 AND noindex = 0
+			AND status_code >= 200 AND status_code < 300 AND (canonical = "" OR canonical = url) AND crawled = 1
+			AND EXISTS (
+				SELECT 1
+				FROM sitemap_files
+				WHERE crawl_id = ? AND is_index = 0 AND urls > 0
+			)`
+
+	return &report_manager.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id, c.Id),
+		ErrorType: reporter_errors.ErrorNotInSitemap,
+	}
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages that
+// are only reachable through the sitemap. Pages in the sitemap with no internal incoming links
+// are reported, except for the crawl's start URL as it was requested by the crawler once normalized.
+// These pages are not reported as orphan pages.
+func (sr *SqlReporter) SitemapOnlyReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
+	query := `
+		SELECT
+			pagereports.id
+		FROM pagereports
+		LEFT JOIN links ON pagereports.url_hash = links.url_hash and pagereports.crawl_id = links.crawl_id
+		WHERE pagereports.in_sitemap = 1 AND pagereports.crawled = 1 AND links.url IS NULL
+			AND pagereports.url != ? AND pagereports.crawl_id = ?`
+
+	return &report_manager.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.URL, c.Id),
+		ErrorType: reporter_errors.ErrorSitemapOnly,
+	}
+}
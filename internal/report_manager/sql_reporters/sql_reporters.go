@@ -31,10 +31,32 @@ func (sr *SqlReporter) GetAllReporters() []report_manager.MultipageCallback {
 		// Add description issue reporters
 		sr.DuplicatedDescriptionReporter,
 
+		// Add content issue reporters
+		sr.DuplicatedContentReporter,
+		sr.NearDuplicatedContentReporter,
+
 		// Add link issue reporters
 		sr.OrphanPagesReporter,
 		sr.NoFollowIndexableReporter,
 		sr.FollowNoFollowReporter,
+		sr.BrokenExternalLinksReporter,
+		sr.RedirectedExternalLinksReporter,
+
+		// Add resource issue reporters
+		sr.BrokenImagesReporter,
+		sr.BrokenScriptsReporter,
+		sr.BrokenStylesReporter,
+		sr.BrokenAudiosReporter,
+		sr.BrokenVideosReporter,
+		sr.RedirectedImagesReporter,
+		sr.RedirectedScriptsReporter,
+		sr.RedirectedStylesReporter,
+		sr.RedirectedAudiosReporter,
+		sr.RedirectedVideosReporter,
+
+		// Add sitemap coverage reporters
+		sr.NotInSitemapReporter,
+		sr.SitemapOnlyReporter,
 
 		// Add hreflang reporters
 		sr.MissingHrelangReturnLinks,
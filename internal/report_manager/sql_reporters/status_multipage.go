@@ -7,32 +7,32 @@ import (
 )
 
 // Creates a MultipageIssueReporter object that contains the SQL query to check for pages
-// that redirect to pages that are also redirected somewhere else.
+// with a redirect chain of more than one redirect, using the redirect hops recorded by the crawler.
 func (sr *SqlReporter) RedirectChainsReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
 	query := `
 		SELECT
-			a.id
-		FROM pagereports AS a
-		LEFT JOIN pagereports AS b ON a.redirect_hash = b.url_hash
-		WHERE a.redirect_hash != "" AND b.redirect_hash  != "" AND a.crawl_id = ? AND b.crawl_id = ?
-		AND a.crawled = 1 AND b.crawled = 1`
+			pagereport_id
+		FROM redirect_hops
+		WHERE crawl_id = ? AND location != ""
+		GROUP BY pagereport_id
+		HAVING count(*) > 1`
 
 	return &report_manager.MultipageIssueReporter{
-		Pstream:   sr.pageReportsQuery(query, c.Id, c.Id),
+		Pstream:   sr.pageReportsQuery(query, c.Id),
 		ErrorType: reporter_errors.ErrorRedirectChain,
 	}
 }
 
 // Creates a MultipageIssueReporter object that contains the SQL query to check for pages
-// that redirect to pages that are redirected back, creating redirection loops.
+// with a redirect chain that redirects back to one of its previous hops, creating a redirection loop.
 func (sr *SqlReporter) RedirectLoopsReporter(c *models.Crawl) *report_manager.MultipageIssueReporter {
 	query := `
 		SELECT
-			a.id
-		FROM pagereports AS a
-		INNER JOIN pagereports AS b ON a.redirect_hash = b.url_hash AND b.redirect_hash = a.url_hash
-		WHERE a.crawl_id = ? AND b.crawl_id = ?
-		AND a.crawled = 1 AND b.crawled = 1`
+			DISTINCT a.pagereport_id
+		FROM redirect_hops AS a
+		INNER JOIN redirect_hops AS b ON a.pagereport_id = b.pagereport_id
+			AND b.hop <= a.hop AND a.location = b.url
+		WHERE a.crawl_id = ? AND b.crawl_id = ?`
 
 	return &report_manager.MultipageIssueReporter{
 		Pstream:   sr.pageReportsQuery(query, c.Id, c.Id),
@@ -0,0 +1,212 @@
+package scheduler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Max number of years Next will look ahead for a matching time.
+const maxYears = 5
+
+// Predefined schedules that can be used instead of the five fields.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a cron-like schedule with the minute, hour, day of month, month
+// and day of week fields. Each field is stored as a bit set of the allowed values.
+type Schedule struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+type bounds struct {
+	min int
+	max int
+}
+
+// Parse returns a Schedule from a cron-like spec with five space separated fields:
+// minute (0-59), hour (0-23), day of month (1-31), month (1-12) and day of week (0-7).
+// Each field accepts "*", single values, ranges "a-b", steps "*/n" or "a-b/n" and lists "a,b".
+// The predefined schedules @yearly, @monthly, @weekly, @daily and @hourly are also supported.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if m, ok := macros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have 5 fields", spec)
+	}
+
+	s := &Schedule{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+
+	var err error
+	fieldBounds := []bounds{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := []*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		*sets[i], err = parseField(f, fieldBounds[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Both 0 and 7 are valid values for sunday.
+	if s.dow&(1<<7) > 0 {
+		s.dow |= 1
+	}
+
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("schedule %q never runs", spec)
+	}
+
+	return s, nil
+}
+
+// Next returns the first time after t that matches the schedule.
+// It returns a zero time if there's no match in the next maxYears years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// Returns true if the day of t matches the schedule. Like in cron, if both the day of month
+// and the day of week are restricted the day matches if any of them matches.
+func (s *Schedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) > 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) > 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// NextCrawl returns the time of the next crawl for the spec schedule after t.
+// An empty spec returns a null time, meaning the crawl is not scheduled.
+func NextCrawl(spec string, t time.Time) (sql.NullTime, error) {
+	if strings.TrimSpace(spec) == "" {
+		return sql.NullTime{}, nil
+	}
+
+	s, err := Parse(spec)
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+
+	return sql.NullTime{Time: s.Next(t), Valid: true}, nil
+}
+
+// Returns a bit set with the values allowed by a comma separated list of ranges.
+func parseField(field string, b bounds) (uint64, error) {
+	var set uint64
+	for _, r := range strings.Split(field, ",") {
+		bits, err := parseRange(r, b)
+		if err != nil {
+			return 0, err
+		}
+
+		set |= bits
+	}
+
+	return set, nil
+}
+
+// Returns a bit set with the values allowed by a range expression
+// such as "*", "5", "1-5", "*/15" or "1-30/2".
+func parseRange(r string, b bounds) (uint64, error) {
+	step := 1
+	rangeAndStep := strings.Split(r, "/")
+	if len(rangeAndStep) > 2 {
+		return 0, fmt.Errorf("invalid range %q", r)
+	}
+
+	if len(rangeAndStep) == 2 {
+		var err error
+		step, err = strconv.Atoi(rangeAndStep[1])
+		if err != nil || step < 1 {
+			return 0, fmt.Errorf("invalid step in %q", r)
+		}
+	}
+
+	start, end := b.min, b.max
+	if rangeAndStep[0] != "*" {
+		lowAndHigh := strings.Split(rangeAndStep[0], "-")
+		if len(lowAndHigh) > 2 {
+			return 0, fmt.Errorf("invalid range %q", r)
+		}
+
+		var err error
+		start, err = strconv.Atoi(lowAndHigh[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid value in %q", r)
+		}
+
+		end = start
+		if len(lowAndHigh) == 2 {
+			end, err = strconv.Atoi(lowAndHigh[1])
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %q", r)
+			}
+		} else if len(rangeAndStep) == 2 {
+			end = b.max
+		}
+	}
+
+	if start < b.min || end > b.max || start > end {
+		return 0, errors.New("value out of range in " + strconv.Quote(r))
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,86 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/scheduler"
+)
+
+func TestParseErrors(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"0 0 31 2 *",
+		"@sometimes",
+	}
+
+	for _, spec := range specs {
+		if _, err := scheduler.Parse(spec); err == nil {
+			t.Errorf("Parse %q: expected error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2024, 1, 10, 10, 30, 15, 0, time.UTC)
+
+	table := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 10, 10, 31, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 10, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * 1", time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2024, 1, 14, 3, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, 1, 10, 13, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 5", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, v := range table {
+		s, err := scheduler.Parse(v.spec)
+		if err != nil {
+			t.Errorf("Parse %q: %v", v.spec, err)
+			continue
+		}
+
+		if next := s.Next(now); !next.Equal(v.expected) {
+			t.Errorf("Next %q: %v != %v", v.spec, next, v.expected)
+		}
+	}
+}
+
+func TestNextCrawl(t *testing.T) {
+	now := time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)
+
+	next, err := scheduler.NextCrawl("", now)
+	if err != nil || next.Valid {
+		t.Errorf("NextCrawl empty schedule: %v %v", next, err)
+	}
+
+	next, err = scheduler.NextCrawl("@daily", now)
+	if err != nil || !next.Valid {
+		t.Errorf("NextCrawl @daily: %v %v", next, err)
+	}
+
+	_, err = scheduler.NextCrawl("invalid", now)
+	if err == nil {
+		t.Error("NextCrawl invalid schedule: expected error")
+	}
+}
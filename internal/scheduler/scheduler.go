@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Interval between checks for projects with a scheduled crawl.
+const checkInterval = time.Minute
+
+type Storage interface {
+	FindScheduledProjects(time.Time) []models.Project
+	UpdateNextCrawl(*models.Project) error
+}
+
+type Crawler interface {
+	StartCrawler(models.Project) (*models.Crawl, error)
+}
+
+// Scheduler starts the crawls of the projects that have a crawl schedule.
+// The time of each project's next crawl is stored, so scheduled crawls that were
+// missed or interrupted because the server was stopped are started again on restart.
+type Scheduler struct {
+	store   Storage
+	crawler Crawler
+	running map[int64]bool
+	lock    *sync.Mutex
+}
+
+func New(s Storage, c Crawler) *Scheduler {
+	return &Scheduler{
+		store:   s,
+		crawler: c,
+		running: make(map[int64]bool),
+		lock:    &sync.Mutex{},
+	}
+}
+
+// Start checks periodically for projects with a scheduled crawl and starts crawling them.
+func (s *Scheduler) Start() {
+	go func() {
+		s.check(time.Now())
+
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		for t := range ticker.C {
+			s.check(t)
+		}
+	}()
+}
+
+// Starts crawling the projects with a next crawl time before t.
+// Projects that are already being crawled by the scheduler are skipped.
+func (s *Scheduler) check(t time.Time) {
+	for _, p := range s.store.FindScheduledProjects(t) {
+		if !s.setRunning(p.Id) {
+			continue
+		}
+
+		go s.crawl(p)
+	}
+}
+
+// Crawls the project and stores the time of its next crawl once the crawl has ended.
+// If the project is already being crawled the scheduled crawl is skipped.
+func (s *Scheduler) crawl(p models.Project) {
+	defer s.unsetRunning(p.Id)
+
+	log.Printf("Scheduled crawl %s\n", p.URL)
+	crawl, err := s.crawler.StartCrawler(p)
+	if err != nil {
+		log.Printf("Scheduled crawl %s: %v\n", p.URL, err)
+	} else {
+		log.Printf("Scheduled crawl %s: crawled %d pages\n", p.URL, crawl.TotalURLs)
+	}
+
+	p.NextCrawl, err = NextCrawl(p.Schedule, time.Now())
+	if err != nil {
+		log.Printf("Scheduled crawl %s: %v\n", p.URL, err)
+	}
+
+	if err := s.store.UpdateNextCrawl(&p); err != nil {
+		log.Printf("Scheduled crawl %s: %v\n", p.URL, err)
+	}
+}
+
+// Marks the project as running. It returns false if it was already running.
+func (s *Scheduler) setRunning(pid int64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.running[pid] {
+		return false
+	}
+
+	s.running[pid] = true
+
+	return true
+}
+
+func (s *Scheduler) unsetRunning(pid int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.running, pid)
+}
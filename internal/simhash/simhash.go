@@ -0,0 +1,103 @@
+// Package simhash computes SimHash fingerprints of texts, so near-duplicate texts can be
+// found comparing the number of different bits of their fingerprints.
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+)
+
+const (
+	// Number of consecutive words hashed together to compute a fingerprint.
+	shingleSize = 3
+
+	// NearDuplicateDistance is the max number of different bits between the fingerprints
+	// of two near-duplicate texts, a similarity of about 90% of their fingerprints.
+	NearDuplicateDistance = 6
+)
+
+// Fingerprint returns the 64 bit SimHash of the text computed from the hashes of its
+// shingles of consecutive words. It returns 0 if the text has no words.
+func Fingerprint(text string) uint64 {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	for i := 0; i+shingleSize <= len(words) || i == 0; i++ {
+		end := min(i+shingleSize, len(words))
+
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:end], " ")))
+		sum := h.Sum64()
+
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for b, w := range weights {
+		if w > 0 {
+			fingerprint |= 1 << b
+		}
+	}
+
+	return fingerprint
+}
+
+// Distance returns the number of different bits of the fingerprints a and b.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Neighbors returns the keys of the fingerprints within maxDistance of each other.
+// For each key with neighbors, the returned map contains the keys of its neighbors.
+// The fingerprints are split into maxDistance+1 bands, so fingerprints within maxDistance
+// have at least one identical band and only the fingerprints sharing a band are compared.
+func Neighbors(fingerprints map[int64]uint64, maxDistance int) map[int64][]int64 {
+	bands := maxDistance + 1
+	width := 64 / bands
+
+	neighbors := make(map[int64][]int64)
+	seen := make(map[[2]int64]bool)
+
+	for band := 0; band < bands; band++ {
+		shift := band * width
+		mask := uint64(1)<<width - 1
+		if band == bands-1 {
+			mask = ^uint64(0) >> shift
+		}
+
+		buckets := make(map[uint64][]int64)
+		for k, f := range fingerprints {
+			v := (f >> shift) & mask
+			buckets[v] = append(buckets[v], k)
+		}
+
+		for _, keys := range buckets {
+			for i := 0; i < len(keys); i++ {
+				for j := i + 1; j < len(keys); j++ {
+					a, b := min(keys[i], keys[j]), max(keys[i], keys[j])
+					if seen[[2]int64{a, b}] {
+						continue
+					}
+
+					seen[[2]int64{a, b}] = true
+					if Distance(fingerprints[a], fingerprints[b]) <= maxDistance {
+						neighbors[a] = append(neighbors[a], b)
+						neighbors[b] = append(neighbors[b], a)
+					}
+				}
+			}
+		}
+	}
+
+	return neighbors
+}
@@ -0,0 +1,82 @@
+package simhash_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/simhash"
+)
+
+const text = "search engine optimization is the process of improving the quality and quantity " +
+	"of website traffic to a website or a web page from search engines seo targets unpaid " +
+	"traffic rather than direct traffic or paid traffic unpaid traffic may originate from " +
+	"different kinds of searches including image search video search academic search news " +
+	"search and industry specific vertical search engines as an internet marketing strategy " +
+	"seo considers how search engines work the computer programmed algorithms that dictate " +
+	"search engine behavior what people search for the actual search terms or keywords typed " +
+	"into search engines and which search engines are preferred by their targeted audience"
+
+func TestFingerprint(t *testing.T) {
+	if simhash.Fingerprint("") != 0 {
+		t.Errorf("fingerprint of an empty text should be 0")
+	}
+
+	if simhash.Fingerprint(text) != simhash.Fingerprint(text) {
+		t.Errorf("fingerprints of the same text should be equal")
+	}
+
+	near := strings.Replace(text, "academic search", "scholar search", 1)
+	if d := simhash.Distance(simhash.Fingerprint(text), simhash.Fingerprint(near)); d > simhash.NearDuplicateDistance {
+		t.Errorf("near-duplicate distance %d > %d", d, simhash.NearDuplicateDistance)
+	}
+
+	different := "the quick brown fox jumps over the lazy dog while the cat sleeps on the warm sofa all afternoon"
+	if d := simhash.Distance(simhash.Fingerprint(text), simhash.Fingerprint(different)); d <= simhash.NearDuplicateDistance {
+		t.Errorf("different texts distance %d <= %d", d, simhash.NearDuplicateDistance)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	fingerprints := map[int64]uint64{
+		1: 0xF0F0F0F0F0F0F0F0,
+		2: 0xF0F0F0F0F0F0F0F1, // 1 bit from 1
+		3: 0x70F0F0F0F0F0F0F3, // 2 bits from 2 and 3 bits from 1
+		4: 0x0F0F0F0F0F0F0F0F,
+	}
+
+	neighbors := simhash.Neighbors(fingerprints, 3)
+
+	table := []struct {
+		key  int64
+		want []int64
+	}{
+		{1, []int64{2, 3}},
+		{2, []int64{1, 3}},
+		{3, []int64{1, 2}},
+		{4, nil},
+	}
+
+	for _, v := range table {
+		got := neighbors[v.key]
+		if len(got) != len(v.want) {
+			t.Errorf("%d: neighbors %v != %v", v.key, got, v.want)
+			continue
+		}
+
+		for _, w := range v.want {
+			if !contains(got, w) {
+				t.Errorf("%d: neighbors %v should contain %d", v.key, got, w)
+			}
+		}
+	}
+}
+
+func contains(s []int64, v int64) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+
+	return false
+}
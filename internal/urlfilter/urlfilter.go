@@ -0,0 +1,91 @@
+package urlfilter
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Prefix of the rules that are regular expressions instead of glob patterns.
+const regexPrefix = "regex:"
+
+// Filter decides if an URL is in the crawl scope using include and exclude rules.
+// Rules are matched against the URL's path and query string, for instance "/search?q=seo".
+// Glob rules match the whole path and query, with "*" matching any number of characters.
+// Rules starting with "regex:" are regular expressions matching any part of the path and query.
+type Filter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// New returns a Filter with the include and exclude rules, one rule per line.
+// Empty lines are ignored. It returns an error if any rule is not valid.
+func New(include, exclude string) (*Filter, error) {
+	i, err := Parse(include)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := Parse(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{include: i, exclude: e}, nil
+}
+
+// Parse compiles the rules in s, one rule per line, into regular expressions.
+func Parse(s string) ([]*regexp.Regexp, error) {
+	var rules []*regexp.Regexp
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(line), `\*`, ".*") + "$"
+		if strings.HasPrefix(line, regexPrefix) {
+			expr = strings.TrimPrefix(line, regexPrefix)
+		}
+
+		r, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %q: %w", line, err)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, scanner.Err()
+}
+
+// Allowed returns true if the URL matches any of the include rules, or there are
+// no include rules, and it doesn't match any of the exclude rules.
+func (f *Filter) Allowed(u *url.URL) bool {
+	if f == nil {
+		return true
+	}
+
+	s := u.RequestURI()
+
+	if len(f.include) > 0 && !match(f.include, s) {
+		return false
+	}
+
+	return !match(f.exclude, s)
+}
+
+// Returns true if s matches any of the rules.
+func match(rules []*regexp.Regexp, s string) bool {
+	for _, r := range rules {
+		if r.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
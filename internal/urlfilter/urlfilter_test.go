@@ -0,0 +1,66 @@
+package urlfilter_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/urlfilter"
+)
+
+func TestFilter(t *testing.T) {
+	include := "/blog/*\n/search*\n"
+	exclude := "/blog/tag/*\n\nregex:[?&]color="
+
+	f, err := urlfilter.New(include, exclude)
+	if err != nil {
+		t.Fatalf("TestFilter: %v", err)
+	}
+
+	table := []struct {
+		u       string
+		allowed bool
+	}{
+		{"https://example.com/blog/post", true},
+		{"https://example.com/search?q=seo", true},
+		{"https://example.com/blog/tag/seo", false},
+		{"https://example.com/blog/post?size=1&color=red", false},
+		{"https://example.com/about", false},
+		{"https://example.com/", false},
+	}
+
+	for _, v := range table {
+		u, _ := url.Parse(v.u)
+		if f.Allowed(u) != v.allowed {
+			t.Errorf("TestFilter %s: allowed should be %v", v.u, v.allowed)
+		}
+	}
+}
+
+func TestFilterNoIncludeRules(t *testing.T) {
+	f, err := urlfilter.New("", "/search?*")
+	if err != nil {
+		t.Fatalf("TestFilterNoIncludeRules: %v", err)
+	}
+
+	table := []struct {
+		u       string
+		allowed bool
+	}{
+		{"https://example.com/", true},
+		{"https://example.com/search", true},
+		{"https://example.com/search?q=seo", false},
+	}
+
+	for _, v := range table {
+		u, _ := url.Parse(v.u)
+		if f.Allowed(u) != v.allowed {
+			t.Errorf("TestFilterNoIncludeRules %s: allowed should be %v", v.u, v.allowed)
+		}
+	}
+}
+
+func TestParseInvalidRule(t *testing.T) {
+	if _, err := urlfilter.Parse("regex:(unclosed"); err == nil {
+		t.Error("TestParseInvalidRule: error expected")
+	}
+}
@@ -0,0 +1,115 @@
+package urlnormalizer
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Directory index file names removed from the URL's path.
+var indexFiles = map[string]bool{
+	"index.html":   true,
+	"index.htm":    true,
+	"index.php":    true,
+	"default.asp":  true,
+	"default.aspx": true,
+}
+
+// Default ports removed from the URL's host.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Normalizer rewrites URLs so the different variants of an URL are crawled only once.
+// It lowercases the scheme and host, removes default ports, fragments and directory
+// index file names, and sorts the query parameters. Query parameters can be removed
+// by name, or all of them can be ignored.
+type Normalizer struct {
+	stripParams  []string
+	ignoreParams bool
+}
+
+// New returns a Normalizer that removes the query parameters in stripParams.
+// Parameter names ending in "*" remove all the parameters with that prefix, for
+// instance "utm_*". If ignoreParams is true the query string is removed.
+func New(stripParams []string, ignoreParams bool) *Normalizer {
+	n := &Normalizer{ignoreParams: ignoreParams}
+	for _, p := range stripParams {
+		if p = strings.TrimSpace(p); p != "" {
+			n.stripParams = append(n.stripParams, strings.ToLower(p))
+		}
+	}
+
+	return n
+}
+
+// ParseParams returns the parameter names in a comma or newline separated list.
+func ParseParams(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r' || r == ' '
+	})
+}
+
+// Normalize returns a normalized copy of the URL.
+// If the Normalizer is nil the URL is returned unchanged.
+func (n *Normalizer) Normalize(u *url.URL) *url.URL {
+	if n == nil {
+		return u
+	}
+
+	nu := *u
+	nu.Scheme = strings.ToLower(nu.Scheme)
+	nu.Host = strings.ToLower(nu.Host)
+	nu.Fragment = ""
+	nu.RawFragment = ""
+
+	if port := nu.Port(); port != "" && defaultPorts[nu.Scheme] == port {
+		nu.Host = strings.TrimSuffix(nu.Host, ":"+port)
+	}
+
+	if nu.Path == "" {
+		nu.Path = "/"
+	}
+
+	if dir, file := path.Split(nu.Path); indexFiles[strings.ToLower(file)] {
+		nu.Path = dir
+		nu.RawPath = ""
+	}
+
+	if n.ignoreParams {
+		nu.RawQuery = ""
+		nu.ForceQuery = false
+
+		return &nu
+	}
+
+	q := nu.Query()
+	for k := range q {
+		if n.strip(k) {
+			q.Del(k)
+		}
+	}
+
+	// Encode sorts the query parameters by name.
+	nu.RawQuery = q.Encode()
+	nu.ForceQuery = false
+
+	return &nu
+}
+
+// Returns true if the query parameter must be removed.
+func (n *Normalizer) strip(param string) bool {
+	param = strings.ToLower(param)
+	for _, p := range n.stripParams {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(param, prefix) {
+			return true
+		}
+
+		if p == param {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package urlnormalizer_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/urlnormalizer"
+)
+
+func TestNormalize(t *testing.T) {
+	n := urlnormalizer.New(urlnormalizer.ParseParams("utm_*, gclid"), false)
+
+	table := []struct {
+		u    string
+		want string
+	}{
+		{"HTTPS://Example.COM:443/Blog/?b=2&a=1#top", "https://example.com/Blog/?a=1&b=2"},
+		{"http://example.com:80", "http://example.com/"},
+		{"http://example.com:8080/", "http://example.com:8080/"},
+		{"https://example.com/blog/index.html", "https://example.com/blog/"},
+		{"https://example.com/index.php?utm_source=news&utm_medium=mail&id=1", "https://example.com/?id=1"},
+		{"https://example.com/page?gclid=abc", "https://example.com/page"},
+		{"https://example.com/page?", "https://example.com/page"},
+	}
+
+	for _, v := range table {
+		u, _ := url.Parse(v.u)
+		if got := n.Normalize(u).String(); got != v.want {
+			t.Errorf("TestNormalize %s: got %s want %s", v.u, got, v.want)
+		}
+	}
+}
+
+func TestNormalizeIgnoreParams(t *testing.T) {
+	n := urlnormalizer.New(nil, true)
+
+	u, _ := url.Parse("https://example.com/search?q=seo&page=2")
+	if got := n.Normalize(u).String(); got != "https://example.com/search" {
+		t.Errorf("TestNormalizeIgnoreParams: got %s", got)
+	}
+}
+
+func TestNormalizeNil(t *testing.T) {
+	var n *urlnormalizer.Normalizer
+
+	u, _ := url.Parse("https://Example.com/?b=2&a=1")
+	if got := n.Normalize(u).String(); got != u.String() {
+		t.Errorf("TestNormalizeNil: got %s", got)
+	}
+}
@@ -5,8 +5,10 @@ import (
 )
 
 type URLStorage struct {
-	seen map[string]bool
-	lock sync.RWMutex
+	seen  map[string]bool
+	added []string
+	track bool
+	lock  sync.RWMutex
 }
 
 func New() *URLStorage {
@@ -31,9 +33,32 @@ func (s *URLStorage) Add(u string) {
 
 	if !s.seen[u] {
 		s.seen[u] = true
+
+		if s.track {
+			s.added = append(s.added, u)
+		}
 	}
 }
 
+// Track starts keeping a list of the URL strings added from now on, which is returned by Added.
+func (s *URLStorage) Track() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.track = true
+}
+
+// Returns the URL strings added since the previous call, or since Track was called.
+func (s *URLStorage) Added() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	added := s.added
+	s.added = nil
+
+	return added
+}
+
 // Iterate over the seen map, applying the provided function f to the iteration's current element.
 func (s *URLStorage) Iterate(f func(string)) {
 	s.lock.RLock()
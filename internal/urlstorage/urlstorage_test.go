@@ -29,3 +29,22 @@ func TestURLStorage(t *testing.T) {
 		t.Errorf("Expected %s to be in the seen map", url)
 	}
 }
+
+func TestURLStorageAdded(t *testing.T) {
+	s := urlstorage.New()
+	s.Add("http://example.com/before")
+
+	s.Track()
+	s.Add("http://example.com/1")
+	s.Add("http://example.com/1")
+	s.Add("http://example.com/2")
+
+	added := s.Added()
+	if len(added) != 2 || added[0] != "http://example.com/1" || added[1] != "http://example.com/2" {
+		t.Errorf("Expected the URLs added after Track, got %v", added)
+	}
+
+	if added := s.Added(); len(added) != 0 {
+		t.Errorf("Expected no URLs added since the previous call, got %v", added)
+	}
+}
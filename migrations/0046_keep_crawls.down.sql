@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `keep_crawls`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `keep_crawls` int NOT NULL DEFAULT '2';
\ No newline at end of file
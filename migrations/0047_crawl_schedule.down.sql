@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `crawl_schedule`, DROP COLUMN `next_crawl`;
\ No newline at end of file
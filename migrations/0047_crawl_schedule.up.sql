@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `crawl_schedule` varchar(256) NOT NULL DEFAULT '', ADD COLUMN `next_crawl` timestamp NULL DEFAULT NULL;
\ No newline at end of file
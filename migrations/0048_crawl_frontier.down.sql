@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `crawl_frontier`;
+
+ALTER TABLE `crawls` DROP COLUMN `checkpoint_pagereport_id`, DROP COLUMN `checkpoint_crawled`;
\ No newline at end of file
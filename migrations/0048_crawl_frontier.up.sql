@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `crawl_frontier` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `depth` int NOT NULL DEFAULT '0',
+  `queued` tinyint NOT NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  KEY `crawl_frontier_crawl` (`crawl_id`),
+  CONSTRAINT `crawl_frontier_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
+
+ALTER TABLE `crawls` ADD COLUMN `checkpoint_pagereport_id` int unsigned NULL DEFAULT NULL, ADD COLUMN `checkpoint_crawled` int NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `max_pagereports`, DROP COLUMN `max_depth`, DROP COLUMN `crawl_threads`, DROP COLUMN `crawl_delay`, DROP COLUMN `crawl_timeout`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `max_pagereports` int NOT NULL DEFAULT '20000', ADD COLUMN `max_depth` int NOT NULL DEFAULT '0', ADD COLUMN `crawl_threads` int NOT NULL DEFAULT '2', ADD COLUMN `crawl_delay` int NOT NULL DEFAULT '1500', ADD COLUMN `crawl_timeout` int NOT NULL DEFAULT '10';
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` DROP COLUMN `fetch_error`;
+
+DELETE FROM issue_types WHERE id = 58;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` ADD COLUMN `fetch_error` varchar(64) NOT NULL DEFAULT '';
+
+INSERT INTO issue_types (id, type, priority) VALUES(58, "ERROR_FETCH", 1);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `redirect_hops`;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `redirect_hops` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `hop` smallint unsigned NOT NULL DEFAULT 0,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `status_code` int NOT NULL DEFAULT 0,
+  `location` varchar(2048) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `redirect_hops_pagereport` (`pagereport_id`),
+  KEY `redirect_hops_crawl` (`crawl_id`),
+  CONSTRAINT `redirect_hops_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `redirect_hops_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `render_diffs`;
+
+ALTER TABLE `projects` DROP COLUMN `render_js`;
\ No newline at end of file
@@ -0,0 +1,19 @@
+ALTER TABLE `projects` ADD COLUMN `render_js` tinyint NOT NULL DEFAULT '0';
+
+CREATE TABLE IF NOT EXISTS `render_diffs` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `raw_title` varchar(2048) NOT NULL DEFAULT '',
+  `rendered_title` varchar(2048) NOT NULL DEFAULT '',
+  `raw_links` int NOT NULL DEFAULT 0,
+  `rendered_links` int NOT NULL DEFAULT 0,
+  `added_links` int NOT NULL DEFAULT 0,
+  `raw_words` int NOT NULL DEFAULT 0,
+  `rendered_words` int NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  KEY `render_diffs_pagereport` (`pagereport_id`),
+  KEY `render_diffs_crawl` (`crawl_id`),
+  CONSTRAINT `render_diffs_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `render_diffs_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `crawl_headers`, DROP COLUMN `use_cookies`, DROP COLUMN `login_url`, DROP COLUMN `login_data`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `crawl_headers` varchar(4096) NOT NULL DEFAULT '', ADD COLUMN `use_cookies` tinyint NOT NULL DEFAULT '0', ADD COLUMN `login_url` varchar(2048) NOT NULL DEFAULT '', ADD COLUMN `login_data` varchar(4096) NOT NULL DEFAULT '';
\ No newline at end of file
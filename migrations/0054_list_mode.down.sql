@@ -0,0 +1 @@
+ALTER TABLE `crawls` DROP COLUMN `list_mode`;
\ No newline at end of file
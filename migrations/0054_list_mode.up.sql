@@ -0,0 +1 @@
+ALTER TABLE `crawls` ADD COLUMN `list_mode` tinyint NOT NULL DEFAULT '0';
\ No newline at end of file
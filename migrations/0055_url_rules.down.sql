@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `include_rules`, DROP COLUMN `exclude_rules`;ALTER TABLE `crawls` DROP COLUMN `excluded_by_rules`;
\ No newline at end of file
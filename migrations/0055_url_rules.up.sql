@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `include_rules` varchar(4096) NOT NULL DEFAULT '', ADD COLUMN `exclude_rules` varchar(4096) NOT NULL DEFAULT '';ALTER TABLE `crawls` ADD COLUMN `excluded_by_rules` int NOT NULL DEFAULT '0';
\ No newline at end of file
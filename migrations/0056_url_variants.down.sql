@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `url_variants`;
+
+ALTER TABLE `projects` DROP COLUMN `normalize_urls`, DROP COLUMN `strip_params`, DROP COLUMN `ignore_params`;
\ No newline at end of file
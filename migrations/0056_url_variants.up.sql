@@ -0,0 +1,14 @@
+ALTER TABLE `projects` ADD COLUMN `normalize_urls` tinyint NOT NULL DEFAULT '0', ADD COLUMN `strip_params` varchar(1024) NOT NULL DEFAULT '', ADD COLUMN `ignore_params` tinyint NOT NULL DEFAULT '0';
+
+CREATE TABLE IF NOT EXISTS `url_variants` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `url_hash` varchar(256) NOT NULL DEFAULT '',
+  `variant` varchar(2048) NOT NULL DEFAULT '',
+  `variant_hash` varchar(256) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `url_variants_hash` (`crawl_id`, `url_hash`),
+  UNIQUE KEY `url_variants_variant` (`crawl_id`, `variant_hash`),
+  CONSTRAINT `url_variants_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
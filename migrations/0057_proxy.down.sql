@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `proxy_url`;
\ No newline at end of file
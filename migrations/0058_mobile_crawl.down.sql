@@ -0,0 +1,3 @@
+ALTER TABLE `crawls` DROP KEY `crawls_parent`, DROP COLUMN `parent_id`;
+
+ALTER TABLE `projects` DROP COLUMN `mobile_crawl`;
\ No newline at end of file
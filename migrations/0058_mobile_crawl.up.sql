@@ -0,0 +1,3 @@
+ALTER TABLE `projects` ADD COLUMN `mobile_crawl` tinyint NOT NULL DEFAULT '0';
+
+ALTER TABLE `crawls` ADD COLUMN `parent_id` int unsigned NULL DEFAULT NULL, ADD KEY `crawls_parent` (`parent_id`);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` DROP COLUMN `dns_time`, DROP COLUMN `connect_time`, DROP COLUMN `tls_time`, DROP COLUMN `ttfb`, DROP COLUMN `download_time`;
+
+DELETE FROM issue_types WHERE id IN (59, 60);
\ No newline at end of file
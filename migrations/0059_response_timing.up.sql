@@ -0,0 +1,5 @@
+ALTER TABLE `pagereports` ADD COLUMN `dns_time` int NOT NULL DEFAULT '0', ADD COLUMN `connect_time` int NOT NULL DEFAULT '0', ADD COLUMN `tls_time` int NOT NULL DEFAULT '0', ADD COLUMN `ttfb` int NOT NULL DEFAULT '0', ADD COLUMN `download_time` int NOT NULL DEFAULT '0';
+
+INSERT INTO issue_types (id, type, priority) VALUES(59, "ERROR_SLOW_TTFB", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(60, "ERROR_SLOW_DOWNLOAD", 3);
\ No newline at end of file
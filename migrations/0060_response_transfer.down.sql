@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` DROP COLUMN `protocol`, DROP COLUMN `content_encoding`, DROP COLUMN `transfer_size`;
+
+DELETE FROM issue_types WHERE id IN (61, 62);
\ No newline at end of file
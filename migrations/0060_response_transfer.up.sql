@@ -0,0 +1,5 @@
+ALTER TABLE `pagereports` ADD COLUMN `protocol` varchar(16) NOT NULL DEFAULT '', ADD COLUMN `content_encoding` varchar(32) NOT NULL DEFAULT '', ADD COLUMN `transfer_size` int NOT NULL DEFAULT '0';
+
+INSERT INTO issue_types (id, type, priority) VALUES(61, "ERROR_UNCOMPRESSED", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(62, "ERROR_NO_HTTP2", 3);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `tls_certificates`;
+
+DELETE FROM issue_types WHERE id IN (63, 64, 65, 66);
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS `tls_certificates` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `host` varchar(256) NOT NULL DEFAULT '',
+  `subject` varchar(512) NOT NULL DEFAULT '',
+  `issuer` varchar(512) NOT NULL DEFAULT '',
+  `not_after` datetime NOT NULL,
+  `dns_names` text NOT NULL,
+  `valid_chain` tinyint NOT NULL DEFAULT '0',
+  `chain_error` varchar(512) NOT NULL DEFAULT '',
+  `hostname_match` tinyint NOT NULL DEFAULT '0',
+  `version` varchar(16) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `tls_certificates_host` (`crawl_id`, `host`),
+  CONSTRAINT `tls_certificates_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(63, "ERROR_CERT_EXPIRING", 1);
+
+INSERT INTO issue_types (id, type, priority) VALUES(64, "ERROR_CERT_HOSTNAME_MISMATCH", 1);
+
+INSERT INTO issue_types (id, type, priority) VALUES(65, "ERROR_CERT_INCOMPLETE_CHAIN", 1);
+
+INSERT INTO issue_types (id, type, priority) VALUES(66, "ERROR_DEPRECATED_TLS", 2);
\ No newline at end of file
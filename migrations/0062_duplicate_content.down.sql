@@ -0,0 +1,3 @@
+ALTER TABLE `pagereports` DROP KEY `content_hash`, DROP COLUMN `content_hash`, DROP COLUMN `simhash`;
+
+DELETE FROM issue_types WHERE id IN (67, 68);
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE `pagereports` ADD COLUMN `content_hash` varchar(64) NOT NULL DEFAULT '', ADD COLUMN `simhash` bigint unsigned NOT NULL DEFAULT '0', ADD KEY `content_hash` (`crawl_id`, `content_hash`);
+
+INSERT INTO issue_types (id, type, priority) VALUES(67, "ERROR_DUPLICATED_CONTENT", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(68, "ERROR_NEAR_DUPLICATED_CONTENT", 3);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS `external_link_statuses`;
+
+ALTER TABLE `external_links` DROP KEY `external_links_hash`, DROP COLUMN `url_hash`;
+
+ALTER TABLE `projects` DROP COLUMN `check_external_links`;
+
+DELETE FROM issue_types WHERE id IN (69, 70);
\ No newline at end of file
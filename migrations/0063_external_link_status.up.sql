@@ -0,0 +1,20 @@
+ALTER TABLE `projects` ADD COLUMN `check_external_links` tinyint NOT NULL DEFAULT '0';
+
+ALTER TABLE `external_links` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '', ADD KEY `external_links_hash` (`crawl_id`, `url_hash`);
+
+CREATE TABLE IF NOT EXISTS `external_link_statuses` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `url_hash` varchar(256) NOT NULL DEFAULT '',
+  `status_code` int NOT NULL DEFAULT '0',
+  `redirect_url` varchar(2048) NOT NULL DEFAULT '',
+  `fetch_error` varchar(64) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `external_link_statuses_hash` (`crawl_id`, `url_hash`),
+  CONSTRAINT `external_link_statuses_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(69, "ERROR_BROKEN_EXTERNAL_LINKS", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(70, "ERROR_REDIRECTED_EXTERNAL_LINKS", 3);
\ No newline at end of file
@@ -0,0 +1,11 @@
+ALTER TABLE `images` DROP KEY `images_hash`, DROP COLUMN `url_hash`;
+
+ALTER TABLE `scripts` DROP KEY `scripts_hash`, DROP COLUMN `url_hash`;
+
+ALTER TABLE `styles` DROP KEY `styles_hash`, DROP COLUMN `url_hash`;
+
+ALTER TABLE `audios` DROP KEY `audios_hash`, DROP COLUMN `url_hash`;
+
+ALTER TABLE `videos` DROP KEY `videos_hash`, DROP COLUMN `url_hash`;
+
+DELETE FROM issue_types WHERE id IN (71, 72, 73, 74, 75, 76, 77, 78, 79, 80);
\ No newline at end of file
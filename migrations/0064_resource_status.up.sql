@@ -0,0 +1,39 @@
+ALTER TABLE `images` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '', ADD KEY `images_hash` (`crawl_id`, `url_hash`);
+
+UPDATE `images` SET `url_hash` = SHA2(`url`, 256);
+
+ALTER TABLE `scripts` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '', ADD KEY `scripts_hash` (`crawl_id`, `url_hash`);
+
+UPDATE `scripts` SET `url_hash` = SHA2(`url`, 256);
+
+ALTER TABLE `styles` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '', ADD KEY `styles_hash` (`crawl_id`, `url_hash`);
+
+UPDATE `styles` SET `url_hash` = SHA2(`url`, 256);
+
+ALTER TABLE `audios` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '', ADD KEY `audios_hash` (`crawl_id`, `url_hash`);
+
+UPDATE `audios` SET `url_hash` = SHA2(`url`, 256);
+
+ALTER TABLE `videos` ADD COLUMN `url_hash` varchar(256) NOT NULL DEFAULT '', ADD KEY `videos_hash` (`crawl_id`, `url_hash`);
+
+UPDATE `videos` SET `url_hash` = SHA2(`url`, 256);
+
+INSERT INTO issue_types (id, type, priority) VALUES(71, "ERROR_BROKEN_IMAGES", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(72, "ERROR_BROKEN_SCRIPTS", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(73, "ERROR_BROKEN_STYLES", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(74, "ERROR_BROKEN_AUDIOS", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(75, "ERROR_BROKEN_VIDEOS", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(76, "ERROR_REDIRECTED_IMAGES", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(77, "ERROR_REDIRECTED_SCRIPTS", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(78, "ERROR_REDIRECTED_STYLES", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(79, "ERROR_REDIRECTED_AUDIOS", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(80, "ERROR_REDIRECTED_VIDEOS", 3);
\ No newline at end of file
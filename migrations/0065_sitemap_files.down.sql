@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `sitemap_files`;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS `sitemap_files` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `url_hash` varchar(256) NOT NULL DEFAULT '',
+  `index_url` varchar(2048) NOT NULL DEFAULT '',
+  `is_index` tinyint NOT NULL DEFAULT '0',
+  `status_code` int NOT NULL DEFAULT '0',
+  `content_type` varchar(256) NOT NULL DEFAULT '',
+  `compressed` tinyint NOT NULL DEFAULT '0',
+  `size` int NOT NULL DEFAULT '0',
+  `urls` int NOT NULL DEFAULT '0',
+  `invalid_lastmod` int NOT NULL DEFAULT '0',
+  `future_lastmod` int NOT NULL DEFAULT '0',
+  `external_urls` int NOT NULL DEFAULT '0',
+  `duplicate_urls` int NOT NULL DEFAULT '0',
+  `fetch_error` varchar(64) NOT NULL DEFAULT '',
+  `parse_error` varchar(512) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `sitemap_files_hash` (`crawl_id`, `url_hash`),
+  CONSTRAINT `sitemap_files_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
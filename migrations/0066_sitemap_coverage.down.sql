@@ -0,0 +1 @@
+DELETE FROM issue_types WHERE id IN (81, 82);
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO issue_types (id, type, priority) VALUES(81, "ERROR_NOT_IN_SITEMAP", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(82, "ERROR_SITEMAP_ONLY", 3);
\ No newline at end of file
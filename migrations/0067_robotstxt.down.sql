@@ -0,0 +1,3 @@
+ALTER TABLE `projects` DROP COLUMN `robots_draft`;
+
+DROP TABLE IF EXISTS `robotstxt_files`;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE `projects` ADD COLUMN `robots_draft` text NOT NULL;
+
+CREATE TABLE IF NOT EXISTS `robotstxt_files` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `host` varchar(256) NOT NULL DEFAULT '',
+  `status_code` int NOT NULL DEFAULT '0',
+  `draft` tinyint NOT NULL DEFAULT '0',
+  `body` mediumtext NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `robotstxt_files_host` (`crawl_id`, `host`),
+  CONSTRAINT `robotstxt_files_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `projects` DROP COLUMN `robots_token`;
+
+ALTER TABLE `pagereports` MODIFY `robots` varchar(100) DEFAULT NULL, DROP COLUMN `nofollow`, DROP COLUMN `nosnippet`, DROP COLUMN `max_snippet`, DROP COLUMN `unavailable_after`;
\ No newline at end of file
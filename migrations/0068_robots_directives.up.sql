@@ -0,0 +1,3 @@
+ALTER TABLE `projects` ADD COLUMN `robots_token` varchar(64) NOT NULL DEFAULT '';
+
+ALTER TABLE `pagereports` MODIFY `robots` varchar(1024) DEFAULT NULL, ADD COLUMN `nofollow` tinyint NOT NULL DEFAULT '0', ADD COLUMN `nosnippet` tinyint NOT NULL DEFAULT '0', ADD COLUMN `max_snippet` int NOT NULL DEFAULT '0', ADD COLUMN `unavailable_after` datetime DEFAULT NULL;
\ No newline at end of file
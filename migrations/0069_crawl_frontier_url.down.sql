@@ -0,0 +1 @@
+ALTER TABLE `crawl_frontier` MODIFY `url` varchar(2048) NOT NULL DEFAULT '';
\ No newline at end of file
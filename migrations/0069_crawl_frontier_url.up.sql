@@ -0,0 +1 @@
+ALTER TABLE `crawl_frontier` MODIFY `url` text NOT NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `proxy_auth`, ADD COLUMN `login_data` varchar(4096) NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `projects` ADD COLUMN `proxy_auth` tinyint NOT NULL DEFAULT '0', DROP COLUMN `login_data`;
+UPDATE `projects` SET `proxy_auth` = 1, `proxy_url` = CONCAT(SUBSTRING_INDEX(`proxy_url`, '://', 1), '://', SUBSTRING_INDEX(`proxy_url`, '@', -1)) WHERE `proxy_url` LIKE '%@%';
\ No newline at end of file